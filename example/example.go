@@ -3,19 +3,23 @@ package main
 // Crappy example for testing refactor(WIP)!s
 import (
 	"image/color"
+	"time"
 
 	"github.com/hajimehoshi/ebiten/v2"
 	ebitenDraw "github.com/hajimehoshi/ebiten/v2/vector"
 
-	"github.com/kainn9/tteokbokki/physics/detector"
 	entitysubset "github.com/kainn9/tteokbokki/physics/entity_subset"
 	"github.com/kainn9/tteokbokki/physics/factory"
 	"github.com/kainn9/tteokbokki/physics/physics"
-	"github.com/kainn9/tteokbokki/physics/resolver"
+	"github.com/kainn9/tteokbokki/physics/stepper"
+	"github.com/kainn9/tteokbokki/physics/world"
+	transform_components "github.com/kainn9/tteokbokki/transform/components"
 	"github.com/kainn9/tteokbokki/vector"
 )
 
-type game struct{}
+type game struct {
+	lastUpdate time.Time
+}
 
 const (
 	WIDTH          = 640
@@ -98,6 +102,12 @@ var (
 	floorRectEn = entitysubset.NewRigidBody(
 		floorRectTransform, floorRectShape, floorRectPhysics,
 	)
+
+	// World(forces are applied manually below, so no gravity).
+	simWorld = world.NewWorld(0)
+
+	// Runs the world at 60 steps per second no matter the frame rate.
+	simStepper = stepper.NewFixedStepper(1.0/60.0, 5, step)
 )
 
 func (g *game) Layout(w, h int) (int, int) {
@@ -113,12 +123,24 @@ func main() {
 	hexagonEn.SetAngularMass(0)
 	hexagonEn.SetFriction(1)
 
+	simWorld.Add(particleEn)
+	simWorld.Add(rectEn)
+	simWorld.Add(hexagonEn)
+	simWorld.Add(floorRectEn)
+
+	simStepper.Track(particleEn)
+	simStepper.Track(rectEn)
+	simStepper.Track(hexagonEn)
+	simStepper.Track(floorRectEn)
+
 	sim := NewSim()
 	ebiten.RunGame(sim)
 }
 
 func NewSim() *game {
-	g := &game{}
+	g := &game{
+		lastUpdate: time.Now(),
+	}
 
 	ebiten.SetWindowTitle("Testing!")
 	ebiten.SetWindowSize(WIDTH, HEIGHT)
@@ -128,6 +150,20 @@ func NewSim() *game {
 }
 
 func (g *game) Update() error {
+	now := time.Now()
+	frameDt := now.Sub(g.lastUpdate).Seconds()
+	g.lastUpdate = now
+
+	simStepper.Advance(frameDt)
+
+	hexagonEn.SetScale(hexagonEn.Scale().X()+0.001, hexagonEn.Scale().Y()+0.001)
+
+	return nil
+}
+
+// Forces are cleared every step, so they are added right
+// before each one rather than once per frame.
+func step(dt float64) {
 	if ebiten.IsKeyPressed(ebiten.KeyRight) {
 		physics.AddForce(rectEn, vector.NewVec2(20, 0))
 	} else if ebiten.IsKeyPressed(ebiten.KeyLeft) {
@@ -138,30 +174,7 @@ func (g *game) Update() error {
 	physics.AddForce(rectEn, vector.NewVec2(0, 20))
 	physics.AddForce(hexagonEn, vector.NewVec2(0, 20))
 
-	dt := 1.0 / 60.0
-
-	physics.Integrate(particleEn, dt)
-	physics.Integrate(rectEn, dt)
-	physics.Integrate(hexagonEn, dt)
-	physics.Integrate(floorRectEn, dt)
-
-	hexagonEn.SetScale(hexagonEn.Scale().X()+0.001, hexagonEn.Scale().Y()+0.001)
-
-	if isColliding, collision := detector.CheckCollision(
-		rectEn,
-		floorRectEn,
-	); isColliding {
-		resolver.HandleCollision(collision, rectEn, floorRectEn)
-	}
-
-	if isColliding, collision := detector.CheckCollision(
-		hexagonEn,
-		floorRectEn,
-	); isColliding {
-		resolver.HandleCollision(collision, hexagonEn, floorRectEn)
-	}
-
-	return nil
+	simWorld.Step(dt)
 }
 
 func (g *game) Draw(screen *ebiten.Image) {
@@ -184,10 +197,12 @@ func drawParticle(screen *ebiten.Image, particle entitysubset.ParticleFace, colo
 	lineThickness := float32(1)
 	antiAlias := false
 
+	trans := simStepper.Interpolated(particle)
+
 	ebitenDraw.StrokeCircle(
 		screen,
-		float32(particle.Position().X()),
-		float32(particle.Position().Y()),
+		float32(trans.Position().X()),
+		float32(trans.Position().Y()),
 		radius,
 		lineThickness,
 		color,
@@ -199,14 +214,18 @@ func drawPolygonShape(screen *ebiten.Image, shape entitysubset.RigidBodyFace, co
 	lineThickness := float32(1)
 	antiAlias := false
 
-	length := len(shape.Polygon().WorldVertices())
+	// Draw a copy of the polygon placed between the last two steps.
+	interpolated := transform_components.NewPolygonShape(shape.Polygon().LocalVertices()).Polygon()
+	interpolated.UpdateWorldVertices(simStepper.Interpolated(shape))
+
+	length := len(interpolated.WorldVertices())
 
 	for i := 0; i <= length-1; i++ {
-		vert := (shape.Polygon().WorldVertices())[i]
+		vert := (interpolated.WorldVertices())[i]
 
 		nextVertIdx := (i + 1) % length
 
-		vert2 := (shape.Polygon().WorldVertices())[nextVertIdx]
+		vert2 := (interpolated.WorldVertices())[nextVertIdx]
 
 		ebitenDraw.StrokeLine(
 			screen,
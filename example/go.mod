@@ -5,13 +5,13 @@ go 1.21.0
 require (
 	github.com/hajimehoshi/ebiten/v2 v2.6.6
 	github.com/kainn9/tteokbokki/physics v0.0.0-20240224205830-14d6e256a346
+	github.com/kainn9/tteokbokki/transform v0.0.0-20240224203500-7691e4b20bb4
 	github.com/kainn9/tteokbokki/vector v0.0.0-20240224205830-14d6e256a346
 )
 
 require (
 	github.com/ebitengine/purego v0.6.0 // indirect
 	github.com/jezek/xgb v1.1.0 // indirect
-	github.com/kainn9/tteokbokki/transform v0.0.0-20240224203500-7691e4b20bb4 // indirect
 	github.com/kainn9/tteokbokki/transform_shape v0.0.0-20240224202709-9197cd18d33b // indirect
 	golang.org/x/exp/shiny v0.0.0-20230817173708-d852ddb80c63 // indirect
 	golang.org/x/image v0.12.0 // indirect
@@ -0,0 +1,136 @@
+package broadphase
+
+import (
+	"math"
+
+	entitysubset "github.com/kainn9/tteokbokki/physics/entity_subset"
+	"github.com/kainn9/tteokbokki/vector"
+)
+
+// World space axis aligned bounds. Unlike transform_components.AAB,
+// which only stores a size, this is positioned and fits any shape.
+type AABB struct {
+	MinX, MinY, MaxX, MaxY float64
+}
+
+func NewAABB(minX, minY, maxX, maxY float64) AABB {
+	return AABB{minX, minY, maxX, maxY}
+}
+
+// Tight bounds around the bodies current world shape.
+func NewBodyAABB(body entitysubset.RigidBodyFace) AABB {
+	pos := body.Position()
+
+	if body.Circle() != nil {
+		radius := body.Circle().Radius()
+
+		return NewAABB(
+			pos.X()-radius, pos.Y()-radius,
+			pos.X()+radius, pos.Y()+radius,
+		)
+	}
+
+	aabb := NewAABB(math.Inf(1), math.Inf(1), math.Inf(-1), math.Inf(-1))
+
+	for _, vert := range body.Polygon().WorldVertices() {
+		aabb.MinX = math.Min(aabb.MinX, vert.X())
+		aabb.MinY = math.Min(aabb.MinY, vert.Y())
+		aabb.MaxX = math.Max(aabb.MaxX, vert.X())
+		aabb.MaxY = math.Max(aabb.MaxY, vert.Y())
+	}
+
+	return aabb
+}
+
+func (a AABB) Overlaps(b AABB) bool {
+	return a.MinX <= b.MaxX && a.MaxX >= b.MinX &&
+		a.MinY <= b.MaxY && a.MaxY >= b.MinY
+}
+
+// Slab test against the segment from start to end.
+func (a AABB) IntersectsSegment(start, end vector.Vec2Face) bool {
+	_, _, ok := a.clipSegment(start, end)
+
+	return ok
+}
+
+// Fractions of the segment from start to end where it enters and
+// leaves the bounds, ok is false when it misses them.
+func (a AABB) clipSegment(start, end vector.Vec2Face) (tMin, tMax float64, ok bool) {
+	tMin, tMax = 0.0, 1.0
+
+	slabs := [2][4]float64{
+		{start.X(), end.X() - start.X(), a.MinX, a.MaxX},
+		{start.Y(), end.Y() - start.Y(), a.MinY, a.MaxY},
+	}
+
+	for _, slab := range slabs {
+		origin, delta, min, max := slab[0], slab[1], slab[2], slab[3]
+
+		if delta == 0 {
+			if origin < min || origin > max {
+				return 0, 0, false
+			}
+			continue
+		}
+
+		t1 := (min - origin) / delta
+		t2 := (max - origin) / delta
+
+		tMin = math.Max(tMin, math.Min(t1, t2))
+		tMax = math.Min(tMax, math.Max(t1, t2))
+
+		if tMin > tMax {
+			return 0, 0, false
+		}
+	}
+
+	return tMin, tMax, true
+}
+
+func NewSegmentAABB(start, end vector.Vec2Face) AABB {
+	return NewAABB(
+		math.Min(start.X(), end.X()), math.Min(start.Y(), end.Y()),
+		math.Max(start.X(), end.X()), math.Max(start.Y(), end.Y()),
+	)
+}
+
+func (a AABB) Contains(b AABB) bool {
+	return a.MinX <= b.MinX && a.MinY <= b.MinY &&
+		a.MaxX >= b.MaxX && a.MaxY >= b.MaxY
+}
+
+// Bounds shared by both, check Overlaps first.
+func (a AABB) Intersection(b AABB) AABB {
+	return NewAABB(
+		math.Max(a.MinX, b.MinX), math.Max(a.MinY, b.MinY),
+		math.Min(a.MaxX, b.MaxX), math.Min(a.MaxY, b.MaxY),
+	)
+}
+
+func (a AABB) Union(b AABB) AABB {
+	return NewAABB(
+		math.Min(a.MinX, b.MinX), math.Min(a.MinY, b.MinY),
+		math.Max(a.MaxX, b.MaxX), math.Max(a.MaxY, b.MaxY),
+	)
+}
+
+func (a AABB) Expand(margin float64) AABB {
+	return NewAABB(
+		a.MinX-margin, a.MinY-margin,
+		a.MaxX+margin, a.MaxY+margin,
+	)
+}
+
+func (a AABB) Width() float64 {
+	return a.MaxX - a.MinX
+}
+
+func (a AABB) Height() float64 {
+	return a.MaxY - a.MinY
+}
+
+// Used as the cost of a tree node, smaller is better.
+func (a AABB) Perimeter() float64 {
+	return 2 * (a.Width() + a.Height())
+}
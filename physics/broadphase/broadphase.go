@@ -0,0 +1,28 @@
+package broadphase
+
+import (
+	entitysubset "github.com/kainn9/tteokbokki/physics/entity_subset"
+	"github.com/kainn9/tteokbokki/vector"
+)
+
+// Finds pairs of bodies whose bounds overlap, so the (much slower)
+// narrow phase in detector only runs on pairs that might collide.
+type BroadphaseFace interface {
+	Insert(body entitysubset.RigidBodyFace)
+	Remove(body entitysubset.RigidBodyFace)
+	// Must be called after a body moves or changes shape.
+	Update(body entitysubset.RigidBodyFace)
+
+	// Pairs whose bounds(possibly grown by a margin) overlap.
+	Pairs() []Pair
+	// Bodies whose bounds overlap the region.
+	Query(aabb AABB) []entitysubset.RigidBodyFace
+	// Bodies whose bounds the segment passes through.
+	QuerySegment(start, end vector.Vec2Face) []entitysubset.RigidBodyFace
+
+	Len() int
+}
+
+type Pair struct {
+	BodyA, BodyB entitysubset.RigidBodyFace
+}
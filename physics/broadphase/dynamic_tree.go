@@ -0,0 +1,425 @@
+package broadphase
+
+import (
+	"math"
+
+	entitysubset "github.com/kainn9/tteokbokki/physics/entity_subset"
+	"github.com/kainn9/tteokbokki/vector"
+)
+
+const nullNode = -1
+
+// Bounding volume hierarchy where every leaf is a body. Leaves store
+// "fat" bounds grown by a margin, so small movements don't require
+// touching the tree at all.
+type DynamicTree struct {
+	nodes    []treeNode
+	root     int
+	freeList int
+	margin   float64
+
+	leaves map[entitysubset.RigidBodyFace]int
+	// Insertion order of bodies, keeps Pairs() deterministic.
+	order []entitysubset.RigidBodyFace
+}
+
+type treeNode struct {
+	aabb                AABB
+	parent, left, right int
+	height              int
+	body                entitysubset.RigidBodyFace
+	nextFree            int
+}
+
+func (n treeNode) isLeaf() bool {
+	return n.left == nullNode
+}
+
+func NewDynamicTree(margin float64) BroadphaseFace {
+	return &DynamicTree{
+		root:     nullNode,
+		freeList: nullNode,
+		margin:   margin,
+		leaves:   make(map[entitysubset.RigidBodyFace]int),
+	}
+}
+
+func (tree *DynamicTree) Insert(body entitysubset.RigidBodyFace) {
+	if _, ok := tree.leaves[body]; ok {
+		return
+	}
+
+	leaf := tree.allocateNode()
+	tree.nodes[leaf].aabb = NewBodyAABB(body).Expand(tree.margin)
+	tree.nodes[leaf].body = body
+
+	tree.insertLeaf(leaf)
+
+	tree.leaves[body] = leaf
+	tree.order = append(tree.order, body)
+}
+
+func (tree *DynamicTree) Remove(body entitysubset.RigidBodyFace) {
+	leaf, ok := tree.leaves[body]
+	if !ok {
+		return
+	}
+
+	tree.removeLeaf(leaf)
+	tree.freeNode(leaf)
+
+	delete(tree.leaves, body)
+
+	for i, b := range tree.order {
+		if b == body {
+			tree.order = append(tree.order[:i], tree.order[i+1:]...)
+			break
+		}
+	}
+}
+
+// Only reinserts the leaf once the body leaves its fat bounds.
+func (tree *DynamicTree) Update(body entitysubset.RigidBodyFace) {
+	leaf, ok := tree.leaves[body]
+	if !ok {
+		return
+	}
+
+	aabb := NewBodyAABB(body)
+
+	if tree.nodes[leaf].aabb.Contains(aabb) {
+		return
+	}
+
+	tree.removeLeaf(leaf)
+	tree.nodes[leaf].aabb = aabb.Expand(tree.margin)
+	tree.insertLeaf(leaf)
+}
+
+func (tree *DynamicTree) Pairs() []Pair {
+	pairs := []Pair{}
+
+	// Each leaf queries the tree, only keeping leaves inserted after
+	// it so every pair is reported once.
+	rank := make(map[entitysubset.RigidBodyFace]int, len(tree.order))
+	for i, body := range tree.order {
+		rank[body] = i
+	}
+
+	for i, body := range tree.order {
+		leaf := tree.leaves[body]
+
+		tree.query(tree.nodes[leaf].aabb, func(other int) {
+			otherBody := tree.nodes[other].body
+
+			if rank[otherBody] > i {
+				pairs = append(pairs, Pair{body, otherBody})
+			}
+		})
+	}
+
+	return pairs
+}
+
+func (tree *DynamicTree) Query(aabb AABB) []entitysubset.RigidBodyFace {
+	bodies := []entitysubset.RigidBodyFace{}
+
+	tree.query(aabb, func(leaf int) {
+		body := tree.nodes[leaf].body
+
+		// Leaves are fat, so check the real bounds too.
+		if NewBodyAABB(body).Overlaps(aabb) {
+			bodies = append(bodies, body)
+		}
+	})
+
+	return bodies
+}
+
+func (tree *DynamicTree) QuerySegment(start, end vector.Vec2Face) []entitysubset.RigidBodyFace {
+	bodies := []entitysubset.RigidBodyFace{}
+
+	tree.walk(
+		func(aabb AABB) bool { return aabb.IntersectsSegment(start, end) },
+		func(leaf int) {
+			body := tree.nodes[leaf].body
+
+			if NewBodyAABB(body).IntersectsSegment(start, end) {
+				bodies = append(bodies, body)
+			}
+		},
+	)
+
+	return bodies
+}
+
+func (tree DynamicTree) Len() int {
+	return len(tree.leaves)
+}
+
+// Fat bounds of the leaf holding body.
+func (tree DynamicTree) FatAABB(body entitysubset.RigidBodyFace) (AABB, bool) {
+	leaf, ok := tree.leaves[body]
+	if !ok {
+		return AABB{}, false
+	}
+
+	return tree.nodes[leaf].aabb, true
+}
+
+func (tree DynamicTree) query(aabb AABB, fn func(leaf int)) {
+	tree.walk(aabb.Overlaps, fn)
+}
+
+// Visits every leaf whose ancestors(and itself) all pass test.
+func (tree DynamicTree) walk(test func(AABB) bool, fn func(leaf int)) {
+	if tree.root == nullNode {
+		return
+	}
+
+	stack := []int{tree.root}
+
+	for len(stack) > 0 {
+		index := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+
+		node := tree.nodes[index]
+
+		if !test(node.aabb) {
+			continue
+		}
+
+		if node.isLeaf() {
+			fn(index)
+			continue
+		}
+
+		stack = append(stack, node.left, node.right)
+	}
+}
+
+func (tree *DynamicTree) allocateNode() int {
+	if tree.freeList == nullNode {
+		tree.nodes = append(tree.nodes, treeNode{})
+		tree.freeList = len(tree.nodes) - 1
+		tree.nodes[tree.freeList].nextFree = nullNode
+	}
+
+	index := tree.freeList
+	tree.freeList = tree.nodes[index].nextFree
+
+	tree.nodes[index] = treeNode{
+		parent:   nullNode,
+		left:     nullNode,
+		right:    nullNode,
+		nextFree: nullNode,
+	}
+
+	return index
+}
+
+func (tree *DynamicTree) freeNode(index int) {
+	tree.nodes[index] = treeNode{
+		height:   -1,
+		nextFree: tree.freeList,
+	}
+	tree.freeList = index
+}
+
+func (tree *DynamicTree) insertLeaf(leaf int) {
+	if tree.root == nullNode {
+		tree.root = leaf
+		tree.nodes[leaf].parent = nullNode
+		return
+	}
+
+	sibling := tree.findBestSibling(tree.nodes[leaf].aabb)
+
+	// Replace sibling with a new parent holding both.
+	oldParent := tree.nodes[sibling].parent
+	newParent := tree.allocateNode()
+
+	tree.nodes[newParent].parent = oldParent
+	tree.nodes[newParent].aabb = tree.nodes[leaf].aabb.Union(tree.nodes[sibling].aabb)
+	tree.nodes[newParent].height = tree.nodes[sibling].height + 1
+	tree.nodes[newParent].left = sibling
+	tree.nodes[newParent].right = leaf
+
+	tree.nodes[sibling].parent = newParent
+	tree.nodes[leaf].parent = newParent
+
+	if oldParent == nullNode {
+		tree.root = newParent
+	} else if tree.nodes[oldParent].left == sibling {
+		tree.nodes[oldParent].left = newParent
+	} else {
+		tree.nodes[oldParent].right = newParent
+	}
+
+	tree.refit(tree.nodes[leaf].parent)
+}
+
+// Walks down the tree picking whichever child grows the least
+// (surface area heuristic), stopping when descending costs more.
+func (tree DynamicTree) findBestSibling(aabb AABB) int {
+	index := tree.root
+
+	for !tree.nodes[index].isLeaf() {
+		node := tree.nodes[index]
+
+		perimeter := node.aabb.Perimeter()
+		combinedPerimeter := node.aabb.Union(aabb).Perimeter()
+
+		// Cost of making a new parent for this node and the leaf.
+		cost := 2 * combinedPerimeter
+
+		// Minimum cost of pushing the leaf further down.
+		inheritanceCost := 2 * (combinedPerimeter - perimeter)
+
+		costLeft := tree.descendCost(node.left, aabb) + inheritanceCost
+		costRight := tree.descendCost(node.right, aabb) + inheritanceCost
+
+		if cost < costLeft && cost < costRight {
+			break
+		}
+
+		if costLeft < costRight {
+			index = node.left
+		} else {
+			index = node.right
+		}
+	}
+
+	return index
+}
+
+func (tree DynamicTree) descendCost(index int, aabb AABB) float64 {
+	child := tree.nodes[index]
+	combined := aabb.Union(child.aabb).Perimeter()
+
+	if child.isLeaf() {
+		return combined
+	}
+
+	return combined - child.aabb.Perimeter()
+}
+
+func (tree *DynamicTree) removeLeaf(leaf int) {
+	if leaf == tree.root {
+		tree.root = nullNode
+		return
+	}
+
+	parent := tree.nodes[leaf].parent
+	grandParent := tree.nodes[parent].parent
+
+	sibling := tree.nodes[parent].left
+	if sibling == leaf {
+		sibling = tree.nodes[parent].right
+	}
+
+	// The sibling takes the parents place.
+	if grandParent == nullNode {
+		tree.root = sibling
+		tree.nodes[sibling].parent = nullNode
+	} else {
+		if tree.nodes[grandParent].left == parent {
+			tree.nodes[grandParent].left = sibling
+		} else {
+			tree.nodes[grandParent].right = sibling
+		}
+
+		tree.nodes[sibling].parent = grandParent
+		tree.refit(grandParent)
+	}
+
+	tree.freeNode(parent)
+	tree.nodes[leaf].parent = nullNode
+}
+
+// Rebalances and recalculates bounds/heights from index up to the root.
+func (tree *DynamicTree) refit(index int) {
+	for index != nullNode {
+		index = tree.balance(index)
+
+		node := &tree.nodes[index]
+		left, right := tree.nodes[node.left], tree.nodes[node.right]
+
+		node.height = 1 + int(math.Max(float64(left.height), float64(right.height)))
+		node.aabb = left.aabb.Union(right.aabb)
+
+		index = node.parent
+	}
+}
+
+// Rotates the taller child up when the children heights differ by
+// more than one. Returns the index of the node now in a's place.
+func (tree *DynamicTree) balance(a int) int {
+	nodeA := tree.nodes[a]
+
+	if nodeA.isLeaf() || nodeA.height < 2 {
+		return a
+	}
+
+	b, c := nodeA.left, nodeA.right
+	diff := tree.nodes[c].height - tree.nodes[b].height
+
+	if diff > 1 {
+		return tree.rotate(a, c, b, true)
+	}
+
+	if diff < -1 {
+		return tree.rotate(a, b, c, false)
+	}
+
+	return a
+}
+
+// Promotes child above a, where other is a's remaining child and
+// childIsRight tells which side of a child was on.
+func (tree *DynamicTree) rotate(a, child, other int, childIsRight bool) int {
+	f, g := tree.nodes[child].left, tree.nodes[child].right
+
+	// Child takes a's place.
+	tree.nodes[child].left = a
+	tree.nodes[child].parent = tree.nodes[a].parent
+	tree.nodes[a].parent = child
+
+	parent := tree.nodes[child].parent
+	if parent == nullNode {
+		tree.root = child
+	} else if tree.nodes[parent].left == a {
+		tree.nodes[parent].left = child
+	} else {
+		tree.nodes[parent].right = child
+	}
+
+	// The taller grandchild stays under child, the other moves to a.
+	keep, move := f, g
+	if tree.nodes[f].height < tree.nodes[g].height {
+		keep, move = g, f
+	}
+
+	tree.nodes[child].right = keep
+
+	if childIsRight {
+		tree.nodes[a].right = move
+	} else {
+		tree.nodes[a].left = move
+	}
+	tree.nodes[move].parent = a
+
+	tree.nodes[a].aabb = tree.nodes[other].aabb.Union(tree.nodes[move].aabb)
+	tree.nodes[a].height = 1 + int(math.Max(
+		float64(tree.nodes[other].height),
+		float64(tree.nodes[move].height),
+	))
+
+	tree.nodes[child].aabb = tree.nodes[a].aabb.Union(tree.nodes[keep].aabb)
+	tree.nodes[child].height = 1 + int(math.Max(
+		float64(tree.nodes[a].height),
+		float64(tree.nodes[keep].height),
+	))
+
+	return child
+}
@@ -0,0 +1,133 @@
+package broadphase
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+	"testing"
+
+	entitysubset "github.com/kainn9/tteokbokki/physics/entity_subset"
+	"github.com/kainn9/tteokbokki/physics/factory"
+	"github.com/kainn9/tteokbokki/vector"
+)
+
+var benchmarkSizes = []int{1000, 5000, 10000}
+
+// Boxes scattered over an area that grows with n, so every size has
+// about the same number of neighbours per body.
+func newScene(n int) []entitysubset.RigidBodyFace {
+	rng := rand.New(rand.NewSource(1))
+	side := math.Sqrt(float64(n)) * 30
+
+	bodies := make([]entitysubset.RigidBodyFace, n)
+	for i := range bodies {
+		trans, shape, phys := factory.Components.NewRigidBodyRectangleComponents(
+			rng.Float64()*side, rng.Float64()*side, 10, 10, 1, 0,
+		)
+		bodies[i] = entitysubset.NewRigidBody(trans, shape, phys)
+	}
+
+	return bodies
+}
+
+func newTree(bodies []entitysubset.RigidBodyFace) BroadphaseFace {
+	tree := NewDynamicTree(10)
+	for _, body := range bodies {
+		tree.Insert(body)
+	}
+
+	return tree
+}
+
+// Every pair checked against every other, what the world did before
+// it had a broadphase(minus the narrow phase on each pair).
+func bruteForcePairs(bodies []entitysubset.RigidBodyFace) []Pair {
+	aabbs := make([]AABB, len(bodies))
+	for i, body := range bodies {
+		aabbs[i] = NewBodyAABB(body)
+	}
+
+	pairs := []Pair{}
+	for i := 0; i < len(bodies); i++ {
+		for j := i + 1; j < len(bodies); j++ {
+			if aabbs[i].Overlaps(aabbs[j]) {
+				pairs = append(pairs, Pair{bodies[i], bodies[j]})
+			}
+		}
+	}
+
+	return pairs
+}
+
+func TestDynamicTreeFindsEveryPair(t *testing.T) {
+	bodies := newScene(500)
+	tree := newTree(bodies)
+
+	found := map[Pair]bool{}
+	for _, pair := range tree.Pairs() {
+		found[pair] = true
+		found[Pair{pair.BodyB, pair.BodyA}] = true
+	}
+
+	for _, pair := range bruteForcePairs(bodies) {
+		if !found[pair] {
+			t.Fatalf("tree missed a pair at %v and %v", pair.BodyA.Position(), pair.BodyB.Position())
+		}
+	}
+}
+
+func BenchmarkDynamicTreeInsert(b *testing.B) {
+	for _, n := range benchmarkSizes {
+		bodies := newScene(n)
+
+		b.Run(fmt.Sprint(n), func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				newTree(bodies)
+			}
+		})
+	}
+}
+
+// Moves every body a little(mostly within the fat bounds) and updates it.
+func BenchmarkDynamicTreeMove(b *testing.B) {
+	for _, n := range benchmarkSizes {
+		bodies := newScene(n)
+		tree := newTree(bodies)
+		offsets := []vector.Vec2Face{vector.NewVec2(3, 2), vector.NewVec2(-3, -2)}
+
+		b.Run(fmt.Sprint(n), func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				offset := offsets[i%2]
+
+				for _, body := range bodies {
+					body.SetPosition(body.Position().Add(offset))
+					tree.Update(body)
+				}
+			}
+		})
+	}
+}
+
+func BenchmarkDynamicTreePairs(b *testing.B) {
+	for _, n := range benchmarkSizes {
+		tree := newTree(newScene(n))
+
+		b.Run(fmt.Sprint(n), func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				tree.Pairs()
+			}
+		})
+	}
+}
+
+func BenchmarkBruteForcePairs(b *testing.B) {
+	for _, n := range benchmarkSizes {
+		bodies := newScene(n)
+
+		b.Run(fmt.Sprint(n), func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				bruteForcePairs(bodies)
+			}
+		})
+	}
+}
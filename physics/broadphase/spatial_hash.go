@@ -0,0 +1,322 @@
+package broadphase
+
+import (
+	"math"
+	"sort"
+
+	entitysubset "github.com/kainn9/tteokbokki/physics/entity_subset"
+	"github.com/kainn9/tteokbokki/vector"
+)
+
+// Uniform grid of square cells where each body is listed in every cell
+// its bounds touch. Works best when bodies are around the cell size,
+// like tiles and crates, and cells are only stored while occupied.
+type SpatialHash struct {
+	cellSize float64
+
+	cells   map[cellKey][]entitysubset.RigidBodyFace
+	proxies map[entitysubset.RigidBodyFace]*hashProxy
+	// Insertion order of bodies, keeps results deterministic.
+	order []entitysubset.RigidBodyFace
+	// Holds every body, queries are clipped to it so huge regions and
+	// segments only cover cells that can be occupied. Only grows between
+	// calls to Pairs, which shrinks it back down.
+	bounds AABB
+}
+
+type cellKey struct {
+	x, y int
+}
+
+type cellRange struct {
+	minX, minY, maxX, maxY int
+}
+
+type hashProxy struct {
+	aabb  AABB
+	cells cellRange
+	rank  int
+}
+
+// Panics when cellSize is not positive.
+func NewSpatialHash(cellSize float64) BroadphaseFace {
+	if !(cellSize > 0) {
+		panic("broadphase: spatial hash cell size must be positive")
+	}
+
+	return &SpatialHash{
+		cellSize: cellSize,
+		cells:    make(map[cellKey][]entitysubset.RigidBodyFace),
+		proxies:  make(map[entitysubset.RigidBodyFace]*hashProxy),
+		bounds:   emptyBounds(),
+	}
+}
+
+func (sh *SpatialHash) Insert(body entitysubset.RigidBodyFace) {
+	if _, ok := sh.proxies[body]; ok {
+		return
+	}
+
+	aabb := NewBodyAABB(body)
+	proxy := &hashProxy{
+		aabb:  aabb,
+		cells: sh.cellRange(aabb),
+		rank:  len(sh.order),
+	}
+
+	sh.proxies[body] = proxy
+	sh.order = append(sh.order, body)
+	sh.bounds = sh.bounds.Union(aabb)
+	sh.addToCells(body, proxy.cells)
+}
+
+func (sh *SpatialHash) Remove(body entitysubset.RigidBodyFace) {
+	proxy, ok := sh.proxies[body]
+	if !ok {
+		return
+	}
+
+	sh.removeFromCells(body, proxy.cells)
+	delete(sh.proxies, body)
+
+	sh.order = append(sh.order[:proxy.rank], sh.order[proxy.rank+1:]...)
+	for i := proxy.rank; i < len(sh.order); i++ {
+		sh.proxies[sh.order[i]].rank = i
+	}
+}
+
+// Only moves the body between cells when its cell range changes.
+func (sh *SpatialHash) Update(body entitysubset.RigidBodyFace) {
+	proxy, ok := sh.proxies[body]
+	if !ok {
+		return
+	}
+
+	proxy.aabb = NewBodyAABB(body)
+	sh.bounds = sh.bounds.Union(proxy.aabb)
+	cells := sh.cellRange(proxy.aabb)
+
+	if cells == proxy.cells {
+		return
+	}
+
+	sh.removeFromCells(body, proxy.cells)
+	sh.addToCells(body, cells)
+	proxy.cells = cells
+}
+
+func (sh *SpatialHash) Pairs() []Pair {
+	pairs := []Pair{}
+	sh.bounds = emptyBounds()
+
+	for _, body := range sh.order {
+		proxy := sh.proxies[body]
+		sh.bounds = sh.bounds.Union(proxy.aabb)
+
+		// Bodies spanning several cells can meet the same other body
+		// more than once.
+		seen := map[entitysubset.RigidBodyFace]bool{}
+
+		sh.forEachCell(proxy.cells, func(key cellKey) {
+			for _, other := range sh.cells[key] {
+				otherProxy := sh.proxies[other]
+
+				if otherProxy.rank <= proxy.rank || seen[other] {
+					continue
+				}
+				seen[other] = true
+
+				if proxy.aabb.Overlaps(otherProxy.aabb) {
+					pairs = append(pairs, Pair{body, other})
+				}
+			}
+		})
+	}
+
+	return pairs
+}
+
+// Bodies whose bounds overlap the region, in insertion order.
+func (sh *SpatialHash) Query(aabb AABB) []entitysubset.RigidBodyFace {
+	bodies := []entitysubset.RigidBodyFace{}
+
+	if !aabb.Overlaps(sh.bounds) {
+		return bodies
+	}
+
+	cells := sh.cellRange(aabb.Intersection(sh.bounds))
+
+	// Regions covering more cells than there are bodies are cheaper to
+	// check body by body. Counted in floats, a single body can still
+	// span more cells than fit in an int.
+	cellCount := float64(cells.maxX-cells.minX+1) * float64(cells.maxY-cells.minY+1)
+	if cellCount > float64(len(sh.order)) {
+		for _, body := range sh.order {
+			if sh.proxies[body].aabb.Overlaps(aabb) {
+				bodies = append(bodies, body)
+			}
+		}
+
+		return bodies
+	}
+
+	seen := map[entitysubset.RigidBodyFace]bool{}
+
+	sh.forEachCell(cells, func(key cellKey) {
+		for _, body := range sh.cells[key] {
+			if seen[body] {
+				continue
+			}
+			seen[body] = true
+
+			if sh.proxies[body].aabb.Overlaps(aabb) {
+				bodies = append(bodies, body)
+			}
+		}
+	})
+
+	sort.Slice(bodies, func(i, j int) bool {
+		return sh.proxies[bodies[i]].rank < sh.proxies[bodies[j]].rank
+	})
+
+	return bodies
+}
+
+// Only visits the cells the segment passes through.
+func (sh *SpatialHash) QuerySegment(start, end vector.Vec2Face) []entitysubset.RigidBodyFace {
+	bodies := []entitysubset.RigidBodyFace{}
+	seen := map[entitysubset.RigidBodyFace]bool{}
+
+	sh.walkSegment(start, end, func(key cellKey) {
+		for _, body := range sh.cells[key] {
+			if seen[body] {
+				continue
+			}
+			seen[body] = true
+
+			if sh.proxies[body].aabb.IntersectsSegment(start, end) {
+				bodies = append(bodies, body)
+			}
+		}
+	})
+
+	sort.Slice(bodies, func(i, j int) bool {
+		return sh.proxies[bodies[i]].rank < sh.proxies[bodies[j]].rank
+	})
+
+	return bodies
+}
+
+func (sh SpatialHash) Len() int {
+	return len(sh.proxies)
+}
+
+func (sh SpatialHash) CellSize() float64 {
+	return sh.cellSize
+}
+
+func (sh SpatialHash) cellRange(aabb AABB) cellRange {
+	return cellRange{
+		minX: int(math.Floor(aabb.MinX / sh.cellSize)),
+		minY: int(math.Floor(aabb.MinY / sh.cellSize)),
+		maxX: int(math.Floor(aabb.MaxX / sh.cellSize)),
+		maxY: int(math.Floor(aabb.MaxY / sh.cellSize)),
+	}
+}
+
+func (SpatialHash) forEachCell(cells cellRange, fn func(key cellKey)) {
+	for x := cells.minX; x <= cells.maxX; x++ {
+		for y := cells.minY; y <= cells.maxY; y++ {
+			fn(cellKey{x, y})
+		}
+	}
+}
+
+// Amanatides–Woo traversal, calls fn for each cell from start to end
+// that lies within the bounds.
+func (sh SpatialHash) walkSegment(start, end vector.Vec2Face, fn func(key cellKey)) {
+	tMin, tMax, ok := sh.bounds.clipSegment(start, end)
+	if !ok {
+		return
+	}
+
+	delta := end.Sub(start)
+	start, end = start.Add(delta.Scale(tMin)), start.Add(delta.Scale(tMax))
+
+	x := int(math.Floor(start.X() / sh.cellSize))
+	y := int(math.Floor(start.Y() / sh.cellSize))
+	endX := int(math.Floor(end.X() / sh.cellSize))
+	endY := int(math.Floor(end.Y() / sh.cellSize))
+
+	stepX, tMaxX, tDeltaX := sh.traversalAxis(x, start.X(), end.X()-start.X())
+	stepY, tMaxY, tDeltaY := sh.traversalAxis(y, start.Y(), end.Y()-start.Y())
+
+	steps := abs(endX-x) + abs(endY-y)
+
+	fn(cellKey{x, y})
+
+	for i := 0; i < steps; i++ {
+		if tMaxX < tMaxY {
+			x += stepX
+			tMaxX += tDeltaX
+		} else {
+			y += stepY
+			tMaxY += tDeltaY
+		}
+
+		fn(cellKey{x, y})
+	}
+}
+
+// Direction to step in, the fraction of the segment until the first
+// cell border, and the fraction needed to cross a whole cell.
+func (sh SpatialHash) traversalAxis(cell int, start, delta float64) (step int, tMax, tDelta float64) {
+	if delta == 0 {
+		return 0, math.Inf(1), math.Inf(1)
+	}
+
+	if delta > 0 {
+		border := float64(cell+1) * sh.cellSize
+		return 1, (border - start) / delta, sh.cellSize / delta
+	}
+
+	border := float64(cell) * sh.cellSize
+	return -1, (border - start) / delta, -sh.cellSize / delta
+}
+
+func (sh *SpatialHash) addToCells(body entitysubset.RigidBodyFace, cells cellRange) {
+	sh.forEachCell(cells, func(key cellKey) {
+		sh.cells[key] = append(sh.cells[key], body)
+	})
+}
+
+func (sh *SpatialHash) removeFromCells(body entitysubset.RigidBodyFace, cells cellRange) {
+	sh.forEachCell(cells, func(key cellKey) {
+		cell := sh.cells[key]
+
+		for i, b := range cell {
+			if b == body {
+				cell = append(cell[:i], cell[i+1:]...)
+				break
+			}
+		}
+
+		if len(cell) == 0 {
+			delete(sh.cells, key)
+		} else {
+			sh.cells[key] = cell
+		}
+	})
+}
+
+func emptyBounds() AABB {
+	return NewAABB(math.Inf(1), math.Inf(1), math.Inf(-1), math.Inf(-1))
+}
+
+func abs(v int) int {
+	if v < 0 {
+		return -v
+	}
+
+	return v
+}
@@ -0,0 +1,62 @@
+package broadphase
+
+import (
+	"testing"
+
+	"github.com/kainn9/tteokbokki/vector"
+)
+
+func newHash(n int) *SpatialHash {
+	hash := NewSpatialHash(20).(*SpatialHash)
+	for _, body := range newScene(n) {
+		hash.Insert(body)
+	}
+
+	return hash
+}
+
+func TestSpatialHashQueryHugeRegion(t *testing.T) {
+	hash := newHash(50)
+
+	if found := hash.Query(NewAABB(-1e30, -1e30, 1e30, 1e30)); len(found) != 50 {
+		t.Errorf("expected all 50 bodies, got %d", len(found))
+	}
+
+	if found := hash.Query(NewAABB(1e29, 1e29, 1e30, 1e30)); len(found) != 0 {
+		t.Errorf("expected no bodies far away from the scene, got %d", len(found))
+	}
+
+	// Moving a body out of the scene grows what queries are clipped to.
+	body := hash.order[0]
+	body.SetPosition(vector.NewVec2(5000, 5000))
+	hash.Update(body)
+
+	if found := hash.Query(NewAABB(4000, 4000, 1e30, 1e30)); len(found) != 1 || found[0] != body {
+		t.Errorf("expected to find the body moved to %v, got %d bodies", body.Position(), len(found))
+	}
+}
+
+func TestSpatialHashLongSegmentOnlyWalksOccupiedCells(t *testing.T) {
+	hash := newHash(50)
+	start, end := vector.NewVec2(-1e8, 100), vector.NewVec2(1e8, 100)
+
+	cells := 0
+	hash.walkSegment(start, end, func(key cellKey) {
+		cells++
+	})
+
+	// The scene is about 210 pixels wide, so a couple dozen cells at most.
+	if cells > 20 {
+		t.Errorf("expected the walk to be clipped to the scene, visited %d cells", cells)
+	}
+
+	for _, body := range hash.QuerySegment(start, end) {
+		if !NewBodyAABB(body).IntersectsSegment(start, end) {
+			t.Errorf("body at %v does not touch the segment", body.Position())
+		}
+	}
+
+	if len(hash.QuerySegment(start, end)) == 0 {
+		t.Error("expected the segment through the scene to hit something")
+	}
+}
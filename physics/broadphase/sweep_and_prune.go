@@ -0,0 +1,199 @@
+package broadphase
+
+import (
+	"sort"
+
+	entitysubset "github.com/kainn9/tteokbokki/physics/entity_subset"
+	"github.com/kainn9/tteokbokki/vector"
+)
+
+type Axis int
+
+const (
+	AxisX Axis = iota
+	AxisY
+)
+
+// Sort and sweep along a single axis. Bodies are kept sorted by the
+// start of their bounds and resorted with insertion sort every query,
+// which is close to linear since the order barely changes between
+// steps. Works best when most motion is along the other axis(or the
+// bodies are spread out along the chosen one), like side-scrollers.
+type SweepAndPrune struct {
+	axis Axis
+
+	proxies []*sapProxy
+	lookup  map[entitysubset.RigidBodyFace]*sapProxy
+
+	// Used to order pairs/queries by insertion.
+	nextRank int
+}
+
+type sapProxy struct {
+	body entitysubset.RigidBodyFace
+	aabb AABB
+	rank int
+}
+
+// Panics when axis is neither AxisX nor AxisY.
+func NewSweepAndPrune(axis Axis) BroadphaseFace {
+	if axis != AxisX && axis != AxisY {
+		panic("broadphase: sweep and prune axis must be AxisX or AxisY")
+	}
+
+	return &SweepAndPrune{
+		axis:   axis,
+		lookup: make(map[entitysubset.RigidBodyFace]*sapProxy),
+	}
+}
+
+func (sap *SweepAndPrune) Insert(body entitysubset.RigidBodyFace) {
+	if _, ok := sap.lookup[body]; ok {
+		return
+	}
+
+	proxy := &sapProxy{
+		body: body,
+		aabb: NewBodyAABB(body),
+		rank: sap.nextRank,
+	}
+	sap.nextRank++
+
+	sap.lookup[body] = proxy
+	sap.proxies = append(sap.proxies, proxy)
+}
+
+func (sap *SweepAndPrune) Remove(body entitysubset.RigidBodyFace) {
+	proxy, ok := sap.lookup[body]
+	if !ok {
+		return
+	}
+
+	delete(sap.lookup, body)
+
+	for i, p := range sap.proxies {
+		if p == proxy {
+			sap.proxies = append(sap.proxies[:i], sap.proxies[i+1:]...)
+			break
+		}
+	}
+}
+
+// Sorting is deferred until the next Pairs/Query call.
+func (sap *SweepAndPrune) Update(body entitysubset.RigidBodyFace) {
+	if proxy, ok := sap.lookup[body]; ok {
+		proxy.aabb = NewBodyAABB(body)
+	}
+}
+
+func (sap *SweepAndPrune) Pairs() []Pair {
+	sap.sort()
+
+	pairs := []Pair{}
+
+	for i, proxy := range sap.proxies {
+		_, max := sap.interval(proxy.aabb)
+
+		// Everything after j starts past proxies end.
+		for j := i + 1; j < len(sap.proxies); j++ {
+			other := sap.proxies[j]
+			otherMin, _ := sap.interval(other.aabb)
+
+			if otherMin > max {
+				break
+			}
+
+			if !proxy.aabb.Overlaps(other.aabb) {
+				continue
+			}
+
+			if proxy.rank < other.rank {
+				pairs = append(pairs, Pair{proxy.body, other.body})
+			} else {
+				pairs = append(pairs, Pair{other.body, proxy.body})
+			}
+		}
+	}
+
+	return pairs
+}
+
+// Bodies whose bounds overlap the region, in insertion order.
+func (sap *SweepAndPrune) Query(aabb AABB) []entitysubset.RigidBodyFace {
+	return sap.sweep(aabb, aabb.Overlaps)
+}
+
+func (sap *SweepAndPrune) QuerySegment(start, end vector.Vec2Face) []entitysubset.RigidBodyFace {
+	return sap.sweep(
+		NewSegmentAABB(start, end),
+		func(aabb AABB) bool { return aabb.IntersectsSegment(start, end) },
+	)
+}
+
+// Collects every proxy starting before bounds ends that passes test.
+func (sap *SweepAndPrune) sweep(bounds AABB, test func(AABB) bool) []entitysubset.RigidBodyFace {
+	sap.sort()
+
+	_, boundsMax := sap.interval(bounds)
+	found := []*sapProxy{}
+
+	for _, proxy := range sap.proxies {
+		min, _ := sap.interval(proxy.aabb)
+
+		if min > boundsMax {
+			break
+		}
+
+		if test(proxy.aabb) {
+			found = append(found, proxy)
+		}
+	}
+
+	sort.Slice(found, func(i, j int) bool {
+		return found[i].rank < found[j].rank
+	})
+
+	bodies := make([]entitysubset.RigidBodyFace, len(found))
+	for i, proxy := range found {
+		bodies[i] = proxy.body
+	}
+
+	return bodies
+}
+
+func (sap SweepAndPrune) Len() int {
+	return len(sap.proxies)
+}
+
+func (sap SweepAndPrune) Axis() Axis {
+	return sap.axis
+}
+
+// Insertion sort, cheap on the previous steps already sorted order.
+func (sap *SweepAndPrune) sort() {
+	for i := 1; i < len(sap.proxies); i++ {
+		proxy := sap.proxies[i]
+		min, _ := sap.interval(proxy.aabb)
+
+		j := i - 1
+		for ; j >= 0; j-- {
+			otherMin, _ := sap.interval(sap.proxies[j].aabb)
+
+			if otherMin <= min {
+				break
+			}
+
+			sap.proxies[j+1] = sap.proxies[j]
+		}
+
+		sap.proxies[j+1] = proxy
+	}
+}
+
+func (sap SweepAndPrune) interval(aabb AABB) (min, max float64) {
+	if sap.axis == AxisY {
+		return aabb.MinY, aabb.MaxY
+	}
+
+	return aabb.MinX, aabb.MaxX
+}
@@ -0,0 +1,26 @@
+package physics_components
+
+type BodyType int
+
+const (
+	// Never moves, zero velocity and infinite mass.
+	StaticBody BodyType = iota
+	// Moved only by its velocity, pushes dynamic bodies without
+	// ever reacting to them. Forces and gravity are ignored.
+	KinematicBody
+	// Moved by forces and collisions.
+	DynamicBody
+)
+
+func (bt BodyType) String() string {
+	switch bt {
+	case StaticBody:
+		return "static"
+	case KinematicBody:
+		return "kinematic"
+	case DynamicBody:
+		return "dynamic"
+	}
+
+	return "unknown"
+}
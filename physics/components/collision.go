@@ -9,10 +9,18 @@ import (
 type Collision struct {
 	Start, End, Normal vector.Vec2Face
 	Depth              float64
+	// Identifies the edges/vertices that produced the contact, it stays
+	// the same across steps while the same features keep touching.
+	ID uint32
 }
 
 func NewCollision(start, end, normal vector.Vec2Face, depth float64) *Collision {
-	return &Collision{start, end, normal, depth}
+	return &Collision{
+		Start:  start,
+		End:    end,
+		Normal: normal,
+		Depth:  depth,
+	}
 }
 
 func (c *Collision) Snap() *Collision {
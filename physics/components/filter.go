@@ -0,0 +1,27 @@
+package physics_components
+
+// Decides which bodies are allowed to collide. Two bodies sharing a
+// non zero group always collide when the group is positive and never
+// when it is negative, otherwise each bodies category has to be in the
+// others mask.
+type Filter struct {
+	CategoryBits uint16
+	MaskBits     uint16
+	GroupIndex   int16
+}
+
+// In category 1 and colliding with everything.
+func NewFilter() Filter {
+	return Filter{
+		CategoryBits: 0x0001,
+		MaskBits:     0xFFFF,
+	}
+}
+
+func (a Filter) ShouldCollide(b Filter) bool {
+	if a.GroupIndex == b.GroupIndex && a.GroupIndex != 0 {
+		return a.GroupIndex > 0
+	}
+
+	return a.MaskBits&b.CategoryBits != 0 && b.MaskBits&a.CategoryBits != 0
+}
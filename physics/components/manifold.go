@@ -0,0 +1,26 @@
+package physics_components
+
+import "github.com/kainn9/tteokbokki/vector"
+
+// All contact points(at most two) between a pair of bodies,
+// sharing one normal pointing from A to B.
+type Manifold struct {
+	Normal   vector.Vec2Face
+	Contacts []*Collision
+}
+
+func NewManifold(normal vector.Vec2Face, contacts ...*Collision) *Manifold {
+	return &Manifold{normal, contacts}
+}
+
+func (m Manifold) Deepest() *Collision {
+	var deepest *Collision
+
+	for _, c := range m.Contacts {
+		if deepest == nil || c.Depth > deepest.Depth {
+			deepest = c
+		}
+	}
+
+	return deepest
+}
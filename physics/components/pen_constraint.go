@@ -0,0 +1,41 @@
+package physics_components
+
+import (
+	"github.com/kainn9/tteokbokki/physics/matrix"
+	transform_components "github.com/kainn9/tteokbokki/transform/components"
+	"github.com/kainn9/tteokbokki/vector"
+)
+
+// Penetration constraint built from a collision, points and normal
+// are stored in local space so they follow the bodies while solving.
+type PenConstraint struct {
+	ACollisionPointLocal vector.Vec2Face
+	BCollisionPointLocal vector.Vec2Face
+	Normal               vector.Vec2Face
+	// Rows are the normal[0] and tangent[1], columns are
+	// A's linear/angular velocity followed by B's.
+	Jacobian, JacobianTranspose matrix.MatN
+	// Accumulated impulses along the normal[0] and tangent[1].
+	CachedLambda []float64
+	Friction     float64
+	Elasticity   float64
+	Bias         float64
+}
+
+func NewPenConstraint(
+	c *Collision,
+	transA, transB transform_components.TransformFace,
+) *PenConstraint {
+	return &PenConstraint{
+		// End lies on the surface of A and Start on the surface of B.
+		ACollisionPointLocal: worldToLocal(c.End, transA),
+		BCollisionPointLocal: worldToLocal(c.Start, transB),
+		Normal:               c.Normal.Rotate(-transA.Rotation()),
+		Jacobian:             matrix.NewMatN(2, 6),
+		CachedLambda:         make([]float64, 2),
+	}
+}
+
+func worldToLocal(point vector.Vec2Face, trans transform_components.TransformFace) vector.Vec2Face {
+	return point.Sub(trans.Position()).Rotate(-trans.Rotation())
+}
@@ -9,12 +9,36 @@ import (
 )
 
 type PhysicsFace interface {
+	BodyType() BodyType
+	SetBodyType(BodyType)
+
+	Awake() bool
+	SetAwake(bool)
+
+	CanSleep() bool
+	SetCanSleep(bool)
+
+	SleepTime() float64
+	SetSleepTime(float64)
+
 	UnstoppableLinear() bool
 	SetUnstoppableLinear(bool)
 
 	UnstoppableAngular() bool
 	SetUnstoppableAngular(bool)
 
+	Bullet() bool
+	SetBullet(bool)
+
+	Filter() Filter
+	SetFilter(Filter)
+
+	Sensor() bool
+	SetSensor(bool)
+
+	OneWay() vector.Vec2Face
+	SetOneWay(passDir vector.Vec2Face)
+
 	Accel() vector.Vec2Face
 	SetAccel(vector.Vec2Face)
 
@@ -49,8 +73,25 @@ type PhysicsFace interface {
 }
 
 type Physics struct {
+	bodyType BodyType
+
+	awake, canSleep bool
+	// How long the body has been slow enough to fall asleep.
+	sleepTime float64
+
 	unstoppableLinear, unstoppableAngular bool
 
+	// Opts into continuous collision detection against static bodies.
+	bullet bool
+
+	filter Filter
+
+	// Reports overlaps through the world without being resolved.
+	sensor bool
+
+	// Direction bodies can pass through in, nil for solid bodies.
+	oneWay vector.Vec2Face
+
 	accel, vel, sumForces vector.Vec2Face
 
 	inverseMass float64
@@ -61,21 +102,85 @@ type Physics struct {
 	friction, elasticity float64
 }
 
+// Zero mass makes a static body, which never moves. Bodies with zero
+// mass that should still follow their velocity need SetBodyType(KinematicBody).
 func NewPhysics(mass float64) PhysicsFace {
 
 	inverseMass := mass
+	bodyType := StaticBody
+
 	if mass != 0 {
 		inverseMass = 1 / mass
+		bodyType = DynamicBody
 	}
 
 	return &Physics{
+		bodyType:    bodyType,
+		awake:       true,
+		canSleep:    true,
 		accel:       &vector.Vec2{},
 		vel:         &vector.Vec2{},
 		sumForces:   &vector.Vec2{},
 		inverseMass: inverseMass,
+		filter:      NewFilter(),
+	}
+}
+
+func (physics Physics) BodyType() BodyType {
+	return physics.bodyType
+}
+
+// Static and kinematic bodies keep their mass, but act as if it
+// were infinite until they are made dynamic again.
+func (physics *Physics) SetBodyType(bodyType BodyType) {
+	physics.bodyType = bodyType
+
+	if bodyType == StaticBody {
+		physics.vel = &vector.Vec2{}
+		physics.angularVel = 0
+	}
+}
+
+func (physics Physics) Awake() bool {
+	return physics.awake
+}
+
+// Putting a body to sleep stops it in place until something wakes it.
+func (physics *Physics) SetAwake(awake bool) {
+	physics.sleepTime = 0
+
+	if awake {
+		physics.awake = true
+		return
+	}
+
+	physics.awake = false
+	physics.vel = &vector.Vec2{}
+	physics.angularVel = 0
+	physics.sumForces = &vector.Vec2{}
+	physics.sumTorque = 0
+}
+
+func (physics Physics) CanSleep() bool {
+	return physics.canSleep
+}
+
+func (physics *Physics) SetCanSleep(canSleep bool) {
+	physics.canSleep = canSleep
+
+	if !canSleep {
+		physics.SetAwake(true)
 	}
 }
 
+func (physics Physics) SleepTime() float64 {
+	return physics.sleepTime
+}
+
+func (physics *Physics) SetSleepTime(sleepTime float64) {
+	physics.sleepTime = sleepTime
+}
+
 func (physics Physics) UnstoppableLinear() bool {
 	return physics.unstoppableLinear
 }
@@ -92,8 +197,48 @@ func (physics *Physics) SetUnstoppableAngular(unstoppable bool) {
 	physics.unstoppableAngular = unstoppable
 }
 
+func (physics Physics) Bullet() bool {
+	return physics.bullet
+}
+
+func (physics *Physics) SetBullet(bullet bool) {
+	physics.bullet = bullet
+}
+
+func (physics Physics) Filter() Filter {
+	return physics.filter
+}
+
+func (physics *Physics) SetFilter(filter Filter) {
+	physics.filter = filter
+}
+
+func (physics Physics) Sensor() bool {
+	return physics.sensor
+}
+
+func (physics *Physics) SetSensor(sensor bool) {
+	physics.sensor = sensor
+}
+
+func (physics Physics) OneWay() vector.Vec2Face {
+	return physics.oneWay
+}
+
+// Makes the body a one way platform that other bodies moving along
+// passDir go through, nil makes it solid again. Axis aligned
+// directions work best, since contact normals are snapped to an axis.
+func (physics *Physics) SetOneWay(passDir vector.Vec2Face) {
+	if passDir == nil {
+		physics.oneWay = nil
+		return
+	}
+
+	physics.oneWay = passDir.Norm()
+}
+
 func (physics Physics) IsStatic() bool {
-	return physics.inverseMass == 0
+	return physics.bodyType == StaticBody
 }
 
 func (physics Physics) Accel() vector.Vec2Face {
@@ -121,14 +266,31 @@ func (physics Physics) SumForces() vector.Vec2Face {
 }
 
 func (physics Physics) InverseMass() float64 {
+	if physics.bodyType != DynamicBody {
+		return 0
+	}
+
 	return physics.inverseMass
 }
+
+// A mass of 0 makes dynamic bodies static and any other mass makes
+// static bodies dynamic, kinematic bodies stay kinematic.
 func (physics *Physics) SetMass(mass float64) {
 	if mass == 0 {
 		physics.inverseMass = mass
 	} else {
 		physics.inverseMass = 1 / mass
 	}
+
+	if physics.bodyType == KinematicBody {
+		return
+	}
+
+	if mass == 0 {
+		physics.SetBodyType(StaticBody)
+	} else {
+		physics.SetBodyType(DynamicBody)
+	}
 }
 
 func (physics Physics) AngularVel() float64 {
@@ -156,6 +318,10 @@ func (physics *Physics) SetSumTorque(sumTorque float64) {
 }
 
 func (physics Physics) InverseAngularMass() float64 {
+	if physics.bodyType != DynamicBody {
+		return 0
+	}
+
 	return physics.inverseAngularMass
 }
 
@@ -7,6 +7,9 @@ type CollisionRigidBodyDecoratorFace interface {
 
 	InverseMass() float64
 	InverseAngularMass() float64
+
+	// Deprecated: use InverseAngularMass.
+	InverseMassAngularMass() float64
 }
 
 type CollisionRigidBodyDecorator struct {
@@ -19,20 +22,28 @@ func NewCollisionRigidBodyDecorator(rb entitysubset.RigidBodyFace) CollisionRigi
 	}
 }
 
+// Sleeping bodies act as static until they are woken.
 func (crd CollisionRigidBodyDecorator) InverseMass() float64 {
 
-	if crd.UnstoppableLinear() {
+	if crd.UnstoppableLinear() || !crd.Awake() {
 		return 0
 	}
 
 	return crd.RigidBodyFace.InverseMass()
 }
 
-func (crd CollisionRigidBodyDecorator) InverseMassAngularMass() float64 {
+func (crd CollisionRigidBodyDecorator) InverseAngularMass() float64 {
 
-	if crd.UnstoppableAngular() {
+	if crd.UnstoppableAngular() || !crd.Awake() {
 		return 0
 	}
 
 	return crd.RigidBodyFace.InverseAngularMass()
 }
+
+// Deprecated: use InverseAngularMass. Kept so existing callers still
+// build, unlike the old version it also returns 0 for sleeping and
+// UnstoppableAngular bodies.
+func (crd CollisionRigidBodyDecorator) InverseMassAngularMass() float64 {
+	return crd.InverseAngularMass()
+}
@@ -123,6 +123,51 @@ func CheckCollision(bodyA, bodyB entitysubset.RigidBodyFace) (
 	return false, nil
 }
 
+func CheckManifold_Multi(
+	transA, transB transform_components.TransformFace,
+	shapeA, shapeB transform_components.ShapeFace,
+	physA, physB physics_components.PhysicsFace,
+) (
+	isColliding bool,
+	manifold *physics_components.Manifold,
+) {
+	bodyA := entitysubset.NewRigidBody(
+		transA,
+		shapeA,
+		physA,
+	)
+
+	bodyB := entitysubset.NewRigidBody(
+		transB,
+		shapeB,
+		physB,
+	)
+
+	return CheckManifold(bodyA, bodyB)
+}
+
+// Like CheckCollision, but polygon pairs get every contact point
+// along the touching edges instead of only the deepest one.
+func CheckManifold(bodyA, bodyB entitysubset.RigidBodyFace) (
+	isColliding bool,
+	manifold *physics_components.Manifold,
+) {
+	if isPolygonCollision(bodyA, bodyB) {
+		if !broadPhasePoly(bodyB, bodyA) {
+			return false, nil
+		}
+		return checkPolygonManifold(bodyA.Polygon(), bodyB.Polygon())
+	}
+
+	// Circles only ever touch at a single point.
+	isColliding, collision := CheckCollision(bodyA, bodyB)
+	if !isColliding {
+		return false, nil
+	}
+
+	return true, physics_components.NewManifold(collision.Normal, collision)
+}
+
 func isCircleCollision(shapeA, shapeB transform_components.ShapeFace) bool {
 	return shapeA.Circle() != nil && shapeB.Circle() != nil
 }
@@ -130,9 +175,9 @@ func isCircleCollision(shapeA, shapeB transform_components.ShapeFace) bool {
 func isCirclePolygonCollision(shapeA, shapeB transform_components.ShapeFace) (
 	isCirclePolygonCollision, aIsPolygon, bIsPolygon bool,
 ) {
-	isCirclePolygonCollision = shapeA.Circle() != nil && shapeB.Polygon() != nil
-	aIsPolygon = shapeA.Polygon() != nil
-	bIsPolygon = shapeB.Polygon() != nil
+	aIsPolygon = shapeA.Polygon() != nil && shapeB.Circle() != nil
+	bIsPolygon = shapeB.Polygon() != nil && shapeA.Circle() != nil
+	isCirclePolygonCollision = aIsPolygon || bIsPolygon
 
 	return isCirclePolygonCollision, aIsPolygon, bIsPolygon
 }
@@ -184,7 +229,7 @@ func checkPolygonCircleCollision(
 	collision *physics_components.Collision,
 ) {
 	defer func() {
-		if !swap {
+		if !swap || collision == nil {
 			return
 		}
 
@@ -241,7 +286,7 @@ func checkPolygonCircleCollision(
 				depth := circle.Radius() - mag
 				normal := vertToCircleCenter.Norm()
 				start := circleBody.Position().Add(
-					collision.Normal.Scale(-circle.Radius()),
+					normal.Scale(-circle.Radius()),
 				)
 				end := start.Add(normal.Scale(depth))
 
@@ -343,6 +388,106 @@ func checkPolygonCollision(polygonA, polygonB transform_components.PolygonFace)
 	return true, collision
 }
 
+// How much shallower the overlap along B's face has to be before it is
+// used as the reference instead of A's.
+const (
+	referenceRelativeTolerance = 0.98
+	// In pixels, around the solvers penetration slop.
+	referenceAbsoluteTolerance = 0.5
+)
+
+// Clips the incident edge(the edge of the other polygon facing the
+// reference edge) against the sides of the reference edge, keeping
+// every clipped point that is behind the reference edge.
+func checkPolygonManifold(polygonA, polygonB transform_components.PolygonFace) (
+	isColliding bool,
+	manifold *physics_components.Manifold,
+) {
+	minSepA, referenceEdgeIndexA, _ := findMinSep(polygonA, polygonB)
+
+	if minSepA >= 0 {
+		return false, nil
+	}
+
+	minSepB, referenceEdgeIndexB, _ := findMinSep(polygonB, polygonA)
+
+	if minSepB >= 0 {
+		return false, nil
+	}
+
+	reference, incident := polygonA, polygonB
+	referenceEdgeIndex := referenceEdgeIndexA
+	flip := false
+
+	// B only takes over when clearly better, so near equal faces do not
+	// swap back and forth between steps and change every contact id.
+	if minSepB > referenceRelativeTolerance*minSepA+referenceAbsoluteTolerance {
+		reference, incident = polygonB, polygonA
+		referenceEdgeIndex = referenceEdgeIndexB
+		flip = true
+	}
+
+	referenceEdge, refStart, refEnd := reference.Edge(referenceEdgeIndex)
+	referenceNormal := referenceEdge.Perpendicular().Norm()
+	referenceTangent := referenceEdge.Norm()
+
+	incidentEdgeIndex := findIncidentEdge(incident, referenceNormal)
+	_, incStart, incEnd := incident.Edge(incidentEdgeIndex)
+
+	incidentEnd := (incidentEdgeIndex + 1) % len(incident.WorldVertices())
+
+	clipped := []clipPoint{
+		{point: incStart, vertex: incidentEdgeIndex, plane: -1},
+		{point: incEnd, vertex: incidentEnd, plane: -1},
+	}
+	clipped = clipSegment(clipped, referenceTangent, referenceTangent.ScalarProduct(refStart), 0)
+	clipped = clipSegment(clipped, referenceTangent.Scale(-1), -referenceTangent.ScalarProduct(refEnd), 1)
+
+	// Normal always points from A to B.
+	normal := referenceNormal
+	if flip {
+		normal = referenceNormal.Scale(-1)
+	}
+
+	manifold = physics_components.NewManifold(normal)
+
+	for _, clip := range clipped {
+		point := clip.point
+		sep := point.Sub(refStart).ScalarProduct(referenceNormal)
+
+		if sep > 0 {
+			continue
+		}
+
+		depth := -sep
+		referencePoint := point.Add(referenceNormal.Scale(depth))
+
+		// Start lies on the surface of B, End on the surface of A.
+		start, end := point, referencePoint
+		if flip {
+			start, end = referencePoint, point
+		}
+
+		collision := physics_components.NewCollision(start, end, normal, depth)
+		collision.ID = featureID(flip, referenceEdgeIndex, incidentEdgeIndex, clip)
+
+		manifold.Contacts = append(manifold.Contacts, collision)
+	}
+
+	// Clipping can lose every point to rounding on near
+	// parallel edges, fall back to the single deepest point.
+	if len(manifold.Contacts) == 0 {
+		isColliding, collision := checkPolygonCollision(polygonA, polygonB)
+		if !isColliding {
+			return false, nil
+		}
+
+		return true, physics_components.NewManifold(collision.Normal, collision)
+	}
+
+	return true, manifold
+}
+
 func findMinSep(
 	polygonA, polygonB transform_components.PolygonFace,
 ) (
@@ -0,0 +1,119 @@
+package detector
+
+import (
+	"testing"
+
+	entitysubset "github.com/kainn9/tteokbokki/physics/entity_subset"
+	"github.com/kainn9/tteokbokki/physics/factory"
+	"github.com/kainn9/tteokbokki/vector"
+)
+
+func newBox(x, y, width, height, mass float64) entitysubset.RigidBodyFace {
+	trans, shape, phys := factory.Components.NewRigidBodyRectangleComponents(x, y, width, height, mass, 0)
+	return entitysubset.NewRigidBody(trans, shape, phys)
+}
+
+// Maps each contact id to where the contact is on the floor.
+func contactIDs(t *testing.T, box, floor entitysubset.RigidBodyFace) map[uint32]vector.Vec2Face {
+	t.Helper()
+
+	isColliding, manifold := CheckManifold(box, floor)
+	if !isColliding {
+		t.Fatal("expected the box to touch the floor")
+	}
+
+	ids := map[uint32]vector.Vec2Face{}
+	for _, contact := range manifold.Contacts {
+		if _, ok := ids[contact.ID]; ok {
+			t.Fatalf("duplicate contact id %x", contact.ID)
+		}
+		ids[contact.ID] = contact.Start
+	}
+
+	return ids
+}
+
+func TestManifoldIDsStableAcrossSteps(t *testing.T) {
+	cases := []struct {
+		name   string
+		floorX float64
+	}{
+		{"resting on the floor", 100},
+		// Only one corner is over the floor, so a clip plane
+		// makes the other point.
+		{"hanging over the edge", 0},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			floor := newBox(c.floorX, 100, 200, 20, 0)
+			box := newBox(100, 71, 40, 40, 1)
+
+			before := contactIDs(t, box, floor)
+			if len(before) != 2 {
+				t.Fatalf("expected 2 contacts, got %d", len(before))
+			}
+
+			// A step worth of drift and wobble.
+			box.SetPosition(box.Position().Add(vector.NewVec2(0.3, 0.1)))
+			box.SetRotation(0.002)
+
+			after := contactIDs(t, box, floor)
+			if len(after) != len(before) {
+				t.Fatalf("expected %d contacts, got %d", len(before), len(after))
+			}
+
+			for id, point := range before {
+				moved, ok := after[id]
+				if !ok {
+					t.Fatalf("contact %x disappeared", id)
+				}
+
+				if dist := moved.Sub(point).Mag(); dist > 1 {
+					t.Errorf("contact %x moved %.2f, its id now names another point", id, dist)
+				}
+			}
+		})
+	}
+}
+
+func TestClipSegmentKeepsFeatures(t *testing.T) {
+	segment := []clipPoint{
+		{point: vector.NewVec2(-10, 0), vertex: 2, plane: -1},
+		{point: vector.NewVec2(10, 0), vertex: 3, plane: -1},
+	}
+
+	// Cuts the start off, so the end comes out first.
+	clipped := clipSegment(segment, vector.NewVec2(1, 0), 0, 1)
+
+	if len(clipped) != 2 {
+		t.Fatalf("expected 2 points, got %d", len(clipped))
+	}
+
+	if clipped[0].vertex != 3 {
+		t.Errorf("expected the end vertex to be kept, got vertex %d", clipped[0].vertex)
+	}
+
+	if clipped[1].vertex != -1 || clipped[1].plane != 1 {
+		t.Errorf("expected the intersection to come from plane 1, got %+v", clipped[1])
+	}
+
+	if featureID(false, 0, 0, clipped[0]) == featureID(false, 0, 0, clipped[1]) {
+		t.Error("expected different ids for a vertex and a clip point")
+	}
+}
+
+func TestClosestFeaturesOnTargetSurface(t *testing.T) {
+	moving := newBox(0, 0, 10, 10, 1)
+	target := newBox(20, 3, 10, 10, 0)
+
+	point, normal := closestFeatures(moving, target)
+
+	if point.X() != 15 || point.Y() < -2 || point.Y() > 5 {
+		t.Errorf("expected a point on the targets left face, got %v", point)
+	}
+
+	if !normal.Equal(vector.NewVec2(-1, 0)) {
+		t.Errorf("expected the normal to face moving, got %v", normal)
+	}
+}
@@ -0,0 +1,45 @@
+package detector
+
+import (
+	entitysubset "github.com/kainn9/tteokbokki/physics/entity_subset"
+	transform_components "github.com/kainn9/tteokbokki/transform/components"
+	"github.com/kainn9/tteokbokki/vector"
+)
+
+func ContainsPoint_Multi(
+	trans transform_components.TransformFace,
+	shape transform_components.ShapeFace,
+	point vector.Vec2Face,
+) bool {
+	body := entitysubset.NewRigidBody(trans, shape, nil)
+
+	return ContainsPoint(body, point)
+}
+
+func ContainsPoint(body entitysubset.RigidBodyFace, point vector.Vec2Face) bool {
+	if body.Circle() != nil {
+		return body.Circle().ContainsPoint(body.Position(), point)
+	}
+
+	if body.Polygon() != nil {
+		return body.Polygon().ContainsPoint(point)
+	}
+
+	return false
+}
+
+func Overlaps_Multi(
+	transA, transB transform_components.TransformFace,
+	shapeA, shapeB transform_components.ShapeFace,
+) bool {
+	bodyA := entitysubset.NewRigidBody(transA, shapeA, nil)
+	bodyB := entitysubset.NewRigidBody(transB, shapeB, nil)
+
+	return Overlaps(bodyA, bodyB)
+}
+
+// Cheaper than CheckCollision when only a yes/no is needed, touching
+// counts as overlapping.
+func Overlaps(bodyA, bodyB entitysubset.RigidBodyFace) bool {
+	return Separation(bodyA, bodyB) <= 0
+}
@@ -0,0 +1,35 @@
+package detector
+
+import (
+	entitysubset "github.com/kainn9/tteokbokki/physics/entity_subset"
+	transform_components "github.com/kainn9/tteokbokki/transform/components"
+	"github.com/kainn9/tteokbokki/vector"
+)
+
+func Raycast_Multi(
+	trans transform_components.TransformFace,
+	shape transform_components.ShapeFace,
+	origin, dir vector.Vec2Face,
+	maxDist float64,
+) (hit bool, result transform_components.RaycastHit) {
+	body := entitysubset.NewRigidBody(trans, shape, nil)
+
+	return Raycast(body, origin, dir, maxDist)
+}
+
+// Casts against whichever shape the body has.
+func Raycast(
+	body entitysubset.RigidBodyFace,
+	origin, dir vector.Vec2Face,
+	maxDist float64,
+) (hit bool, result transform_components.RaycastHit) {
+	if body.Circle() != nil {
+		return body.Circle().Raycast(body.Position(), origin, dir, maxDist)
+	}
+
+	if body.Polygon() != nil {
+		return body.Polygon().Raycast(origin, dir, maxDist)
+	}
+
+	return false, result
+}
@@ -0,0 +1,146 @@
+package detector
+
+import (
+	"math"
+
+	entitysubset "github.com/kainn9/tteokbokki/physics/entity_subset"
+	transform_components "github.com/kainn9/tteokbokki/transform/components"
+	"github.com/kainn9/tteokbokki/vector"
+)
+
+// How far past the time of impact the moving body is pushed to get a
+// contact out of the narrow phase, in pixels.
+const shapeCastProbeDepth = 0.1
+
+type ShapeCastHit struct {
+	// Point on the targets surface and its normal, facing the moving body.
+	Point, Normal vector.Vec2Face
+	// Fraction of motion travelled before the impact.
+	Fraction float64
+}
+
+func ShapeCast_Multi(
+	movingTrans, targetTrans transform_components.TransformFace,
+	movingShape, targetShape transform_components.ShapeFace,
+	motion vector.Vec2Face,
+) (hit bool, result ShapeCastHit) {
+	moving := entitysubset.NewRigidBody(movingTrans, movingShape, nil)
+	target := entitysubset.NewRigidBody(targetTrans, targetShape, nil)
+
+	return ShapeCast(moving, target, motion)
+}
+
+// Sweeps moving along motion(translation only) and reports the first
+// time it touches target. Bodies that already overlap hit at fraction 0.
+// Moving is always put back where it started.
+func ShapeCast(
+	moving, target entitysubset.RigidBodyFace,
+	motion vector.Vec2Face,
+) (hit bool, result ShapeCastHit) {
+	if isColliding, collision := CheckCollision(moving, target); isColliding {
+		return true, newShapeCastHit(collision.Start, collision.Normal, 0)
+	}
+
+	hit, toi := TimeOfImpact(moving, target, motion, 0)
+	if !hit {
+		return false, result
+	}
+
+	start := moving.Position().Clone()
+	defer moving.SetPosition(start)
+
+	// Nudge into the target so the narrow phase reports the contact.
+	probe := motion.Norm().Scale(toiTolerance + shapeCastProbeDepth)
+	moving.SetPosition(start.Add(motion.Scale(toi)).Add(probe))
+
+	if isColliding, collision := CheckCollision(moving, target); isColliding {
+		return true, newShapeCastHit(collision.Start, collision.Normal, toi)
+	}
+
+	// Grazing hits can slide past without overlapping, so the closest
+	// features at the time of impact are used instead.
+	moving.SetPosition(start.Add(motion.Scale(toi)))
+	point, normal := closestFeatures(moving, target)
+
+	return true, ShapeCastHit{
+		Point:    point,
+		Normal:   normal,
+		Fraction: toi,
+	}
+}
+
+// Closest point on targets surface to moving(which must not overlap
+// it), and the normal there facing moving.
+func closestFeatures(moving, target entitysubset.RigidBodyFace) (point, normal vector.Vec2Face) {
+	if isCircleCollision(moving, target) {
+		normal = moving.Position().Sub(target.Position()).Norm()
+		return target.Position().Add(normal.Scale(target.Circle().Radius())), normal
+	}
+
+	if target.Circle() != nil {
+		closest := closestPointOnPolygon(target.Position(), moving.Polygon())
+		normal = closest.Sub(target.Position()).Norm()
+		return target.Position().Add(normal.Scale(target.Circle().Radius())), normal
+	}
+
+	if moving.Circle() != nil {
+		point = closestPointOnPolygon(moving.Position(), target.Polygon())
+		return point, moving.Position().Sub(point).Norm()
+	}
+
+	pointOnMoving, pointOnTarget := closestPolygonPoints(moving.Polygon(), target.Polygon())
+
+	return pointOnTarget, pointOnMoving.Sub(pointOnTarget).Norm()
+}
+
+func closestPointOnPolygon(point vector.Vec2Face, polygon transform_components.PolygonFace) vector.Vec2Face {
+	var closest vector.Vec2Face
+	minDistSq := math.Inf(1)
+
+	for i := range polygon.WorldVertices() {
+		_, edgeStart, edgeEnd := polygon.Edge(i)
+		candidate := closestPointOnSegment(point, edgeStart, edgeEnd)
+
+		if distSq := candidate.Sub(point).MagSquared(); distSq < minDistSq {
+			minDistSq = distSq
+			closest = candidate
+		}
+	}
+
+	return closest
+}
+
+// For separated convex polygons the closest points are always a vertex
+// of one and a point on an edge of the other.
+func closestPolygonPoints(polygonA, polygonB transform_components.PolygonFace) (pointA, pointB vector.Vec2Face) {
+	minDistSq := math.Inf(1)
+
+	for _, vert := range polygonA.WorldVertices() {
+		candidate := closestPointOnPolygon(vert, polygonB)
+
+		if distSq := candidate.Sub(vert).MagSquared(); distSq < minDistSq {
+			minDistSq = distSq
+			pointA, pointB = vert.Clone(), candidate
+		}
+	}
+
+	for _, vert := range polygonB.WorldVertices() {
+		candidate := closestPointOnPolygon(vert, polygonA)
+
+		if distSq := candidate.Sub(vert).MagSquared(); distSq < minDistSq {
+			minDistSq = distSq
+			pointA, pointB = candidate, vert.Clone()
+		}
+	}
+
+	return pointA, pointB
+}
+
+// Collision normals point from moving to target, so they get flipped.
+func newShapeCastHit(point, normal vector.Vec2Face, fraction float64) ShapeCastHit {
+	return ShapeCastHit{
+		Point:    point.Clone(),
+		Normal:   normal.Scale(-1),
+		Fraction: fraction,
+	}
+}
@@ -0,0 +1,116 @@
+package detector
+
+import (
+	"math"
+
+	entitysubset "github.com/kainn9/tteokbokki/physics/entity_subset"
+	"github.com/kainn9/tteokbokki/vector"
+)
+
+const (
+	toiMaxIterations = 64
+	// How close to targetSeparation counts as touching, in pixels.
+	toiTolerance = 0.05
+)
+
+// Conservative advancement: repeatedly moves the body along motion by
+// the distance it is guaranteed to be able to travel without hitting
+// target, until it is within targetSeparation of it.
+//
+// Toi is the fraction of motion travelled before the impact. Only
+// translation is swept, and moving is always put back where it started.
+func TimeOfImpact(
+	moving, target entitysubset.RigidBodyFace,
+	motion vector.Vec2Face,
+	targetSeparation float64,
+) (hit bool, toi float64) {
+	motionLen := motion.Mag()
+
+	if motionLen == 0 {
+		return false, 0
+	}
+
+	if !sweptBoundsOverlap(moving, target, motion) {
+		return false, 0
+	}
+
+	start := moving.Position().Clone()
+	defer moving.SetPosition(start)
+
+	for i := 0; i < toiMaxIterations; i++ {
+		sep := Separation(moving, target)
+
+		// Bodies already overlapping deeper than targetSeparation are
+		// left to the regular collision response. One that starts out
+		// touching is only stopped if its motion keeps pushing into
+		// target, since the response may not have stopped it.
+		if i == 0 && sep-targetSeparation <= toiTolerance {
+			if sep < targetSeparation-toiTolerance || !approaching(moving, target, motion, sep) {
+				return false, 0
+			}
+		}
+
+		if sep-targetSeparation <= toiTolerance {
+			return true, toi
+		}
+
+		// Separation never overestimates the real distance, so moving
+		// by it along the motion can not skip past the impact.
+		toi += (sep - targetSeparation) / motionLen
+
+		if toi > 1 {
+			return false, 0
+		}
+
+		moving.SetPosition(start.Add(motion.Scale(toi)))
+	}
+
+	return false, 0
+}
+
+// True when nudging moving along motion brings it closer to target than
+// sep. Leaves moving nudged, the caller puts it back.
+func approaching(moving, target entitysubset.RigidBodyFace, motion vector.Vec2Face, sep float64) bool {
+	moving.SetPosition(moving.Position().Add(motion.Scale(toiTolerance / motion.Mag())))
+
+	return Separation(moving, target) < sep
+}
+
+// Signed distance between two bodies, negative while overlapping. For
+// polygon pairs this is the SAT separation, which is never more than
+// the real distance.
+func Separation(bodyA, bodyB entitysubset.RigidBodyFace) float64 {
+	if isCircleCollision(bodyA, bodyB) {
+		distance := bodyB.Position().Sub(bodyA.Position()).Mag()
+		return distance - bodyA.Circle().Radius() - bodyB.Circle().Radius()
+	}
+
+	if ok, aIsPoly, _ := isCirclePolygonCollision(bodyA, bodyB); ok {
+		polyBody, circleBody := bodyB, bodyA
+		if aIsPoly {
+			polyBody, circleBody = bodyA, bodyB
+		}
+
+		return pointPolygonSeparation(circleBody.Position(), polyBody.Polygon()) -
+			circleBody.Circle().Radius()
+	}
+
+	sepA, _, _ := findMinSep(bodyA.Polygon(), bodyB.Polygon())
+	sepB, _, _ := findMinSep(bodyB.Polygon(), bodyA.Polygon())
+
+	return math.Max(sepA, sepB)
+}
+
+// Cheap rejection using bounding circles around both bodies,
+// with moving's circle stretched along the whole motion.
+func sweptBoundsOverlap(moving, target entitysubset.RigidBodyFace, motion vector.Vec2Face) bool {
+	reach := boundingRadius(moving) + boundingRadius(target)
+
+	closest := closestPointOnSegment(
+		target.Position(),
+		moving.Position(),
+		moving.Position().Add(motion),
+	)
+
+	return closest.Sub(target.Position()).MagSquared() <= reach*reach
+}
@@ -1 +1,153 @@
 package detector
+
+import (
+	"math"
+
+	entitysubset "github.com/kainn9/tteokbokki/physics/entity_subset"
+	transform_components "github.com/kainn9/tteokbokki/transform/components"
+	"github.com/kainn9/tteokbokki/vector"
+)
+
+// Index of the edge whose normal faces most against referenceNormal.
+func findIncidentEdge(
+	polygon transform_components.PolygonFace,
+	referenceNormal vector.Vec2Face,
+) (index int) {
+	minDot := math.MaxFloat64
+
+	for i := range polygon.WorldVertices() {
+		edge, _, _ := polygon.Edge(i)
+		dot := edge.Perpendicular().Norm().ScalarProduct(referenceNormal)
+
+		if dot < minDot {
+			minDot = dot
+			index = i
+		}
+	}
+
+	return index
+}
+
+// A point of the incident edge while it is being clipped, along with
+// the feature it came from so its id stays the same between steps no
+// matter which endpoint got clipped.
+type clipPoint struct {
+	point vector.Vec2Face
+	// Index of the incident vertex, or -1 when made by a clip plane.
+	vertex int
+	// Clip plane(0 or 1) that made the point, or -1 for a vertex.
+	plane int
+}
+
+// Sutherland–Hodgman clip of a segment against the plane
+// normal·p = offset, keeping the side the normal points to.
+func clipSegment(
+	segment []clipPoint,
+	normal vector.Vec2Face,
+	offset float64,
+	plane int,
+) []clipPoint {
+	if len(segment) < 2 {
+		return segment
+	}
+
+	clipped := make([]clipPoint, 0, 2)
+
+	distStart := normal.ScalarProduct(segment[0].point) - offset
+	distEnd := normal.ScalarProduct(segment[1].point) - offset
+
+	if distStart >= 0 {
+		clipped = append(clipped, segment[0])
+	}
+
+	if distEnd >= 0 {
+		clipped = append(clipped, segment[1])
+	}
+
+	// Points on opposite sides, add the intersection.
+	if distStart*distEnd < 0 {
+		t := distStart / (distStart - distEnd)
+		start, end := segment[0].point, segment[1].point
+		intersection := start.Add(end.Sub(start).Scale(t))
+		clipped = append(clipped, clipPoint{point: intersection, vertex: -1, plane: plane})
+	}
+
+	return clipped
+}
+
+// Packs the features of a clipped contact into a single id. Points made
+// by a clip plane are told apart from incident vertices by the high bit.
+func featureID(flip bool, referenceEdgeIndex, incidentEdgeIndex int, point clipPoint) uint32 {
+	feature := uint32(point.vertex & 0x7f)
+	if point.vertex < 0 {
+		feature = 0x80 | uint32(point.plane&0x7f)
+	}
+
+	id := uint32(referenceEdgeIndex&0xff)<<16 |
+		uint32(incidentEdgeIndex&0xff)<<8 |
+		feature
+
+	if flip {
+		id |= 1 << 24
+	}
+
+	return id
+}
+
+// Signed distance from point to the polygons surface, negative inside.
+func pointPolygonSeparation(
+	point vector.Vec2Face,
+	polygon transform_components.PolygonFace,
+) float64 {
+	maxSep := -math.MaxFloat64
+
+	for i, vert := range polygon.WorldVertices() {
+		edge, _, _ := polygon.Edge(i)
+		sep := point.Sub(vert).ScalarProduct(edge.Perpendicular().Norm())
+		maxSep = math.Max(maxSep, sep)
+	}
+
+	if maxSep <= 0 {
+		return maxSep
+	}
+
+	// Outside, the closest edge(or corner) gives the real distance.
+	minDistSq := math.MaxFloat64
+
+	for i := range polygon.WorldVertices() {
+		_, v1, v2 := polygon.Edge(i)
+		distSq := point.Sub(closestPointOnSegment(point, v1, v2)).MagSquared()
+		minDistSq = math.Min(minDistSq, distSq)
+	}
+
+	return math.Sqrt(minDistSq)
+}
+
+func closestPointOnSegment(point, segStart, segEnd vector.Vec2Face) vector.Vec2Face {
+	segment := segEnd.Sub(segStart)
+	lenSq := segment.MagSquared()
+
+	if lenSq == 0 {
+		return segStart.Clone()
+	}
+
+	t := point.Sub(segStart).ScalarProduct(segment) / lenSq
+	t = math.Max(0, math.Min(1, t))
+
+	return segStart.Add(segment.Scale(t))
+}
+
+// Radius of a circle around the bodies position containing the whole shape.
+func boundingRadius(body entitysubset.RigidBodyFace) float64 {
+	if body.Circle() != nil {
+		return body.Circle().Radius()
+	}
+
+	radius := 0.0
+
+	for _, vert := range body.Polygon().WorldVertices() {
+		radius = math.Max(radius, vert.Sub(body.Position()).Mag())
+	}
+
+	return radius
+}
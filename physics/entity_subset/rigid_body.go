@@ -38,6 +38,11 @@ func NewRigidBody(
 }
 
 func (rb RigidBody) UpdateWorldVertices() {
+	// Circles have no vertices to update.
+	if rb.Polygon() == nil {
+		return
+	}
+
 	rb.Polygon().UpdateWorldVertices(rb.TransformFace)
 }
 
@@ -37,6 +37,19 @@ func (cf componentFactory) NewRigidBodyRectangleComponents(x, y, width, height,
 	return trans, shape, phys
 }
 
+func (cf componentFactory) NewRigidBodyCircleComponents(x, y, radius, mass, rotation float64) (
+	transform_components.TransformFace,
+	transform_components.ShapeFace,
+	physics_components.PhysicsFace,
+) {
+	trans, phys := cf.NewParticleComponents(x, y, mass)
+	trans.SetRotation(rotation)
+
+	shape := transform_components.NewCircleShape(radius)
+
+	return trans, shape, phys
+}
+
 func (cf componentFactory) NewRigidBodyHexagonComponents(x, y, mass, rotation, size float64) (
 	transform_components.TransformFace,
 	transform_components.ShapeFace,
@@ -0,0 +1,105 @@
+package matrix
+
+import (
+	"math"
+
+	"github.com/kainn9/tteokbokki/vector"
+)
+
+// Row major 2x2 matrix.
+type Mat2 [2][2]float64
+
+func NewMat2(a, b, c, d float64) Mat2 {
+	return Mat2{
+		{a, b},
+		{c, d},
+	}
+}
+
+func NewIdentityMat2() Mat2 {
+	return NewMat2(1, 0, 0, 1)
+}
+
+func NewRotationMat2(radians float64) Mat2 {
+	cos, sin := math.Cos(radians), math.Sin(radians)
+
+	return NewMat2(
+		cos, -sin,
+		sin, cos,
+	)
+}
+
+func (m Mat2) Add(other Mat2) Mat2 {
+	for r := 0; r < 2; r++ {
+		for c := 0; c < 2; c++ {
+			m[r][c] += other[r][c]
+		}
+	}
+
+	return m
+}
+
+func (m Mat2) Scale(n float64) Mat2 {
+	for r := 0; r < 2; r++ {
+		for c := 0; c < 2; c++ {
+			m[r][c] *= n
+		}
+	}
+
+	return m
+}
+
+func (m Mat2) Transpose() Mat2 {
+	return NewMat2(
+		m[0][0], m[1][0],
+		m[0][1], m[1][1],
+	)
+}
+
+func (m Mat2) Mul(other Mat2) Mat2 {
+	result := Mat2{}
+
+	for r := 0; r < 2; r++ {
+		for c := 0; c < 2; c++ {
+			result[r][c] = m[r][0]*other[0][c] + m[r][1]*other[1][c]
+		}
+	}
+
+	return result
+}
+
+func (m Mat2) MulVec2(v vector.Vec2Face) vector.Vec2Face {
+	return vector.NewVec2(
+		m[0][0]*v.X()+m[0][1]*v.Y(),
+		m[1][0]*v.X()+m[1][1]*v.Y(),
+	)
+}
+
+func (m Mat2) Determinant() float64 {
+	return m[0][0]*m[1][1] - m[0][1]*m[1][0]
+}
+
+// Returns false when the matrix is singular.
+func (m Mat2) Inverse() (Mat2, bool) {
+	det := m.Determinant()
+
+	if det == 0 {
+		return Mat2{}, false
+	}
+
+	return NewMat2(
+		m[1][1], -m[0][1],
+		-m[1][0], m[0][0],
+	).Scale(1 / det), true
+}
+
+// Solves m * x = b, returning a zero vector when m is singular.
+func (m Mat2) Solve(b vector.Vec2Face) vector.Vec2Face {
+	inverse, ok := m.Inverse()
+
+	if !ok {
+		return vector.NewVec2(0, 0)
+	}
+
+	return inverse.MulVec2(b)
+}
@@ -0,0 +1,157 @@
+package matrix
+
+import (
+	"math"
+
+	"github.com/kainn9/tteokbokki/vector"
+)
+
+// Row major 3x3 matrix, used both for 3 row constraint systems
+// and as a 2D affine transform (rotation + translation).
+type Mat3 [3][3]float64
+
+func NewMat3(
+	a, b, c,
+	d, e, f,
+	g, h, i float64,
+) Mat3 {
+	return Mat3{
+		{a, b, c},
+		{d, e, f},
+		{g, h, i},
+	}
+}
+
+func NewIdentityMat3() Mat3 {
+	return NewMat3(
+		1, 0, 0,
+		0, 1, 0,
+		0, 0, 1,
+	)
+}
+
+func NewRotationMat3(radians float64) Mat3 {
+	cos, sin := math.Cos(radians), math.Sin(radians)
+
+	return NewMat3(
+		cos, -sin, 0,
+		sin, cos, 0,
+		0, 0, 1,
+	)
+}
+
+// Rotates then translates.
+func NewTransformMat3(x, y, radians float64) Mat3 {
+	m := NewRotationMat3(radians)
+	m[0][2] = x
+	m[1][2] = y
+
+	return m
+}
+
+func (m Mat3) Add(other Mat3) Mat3 {
+	for r := 0; r < 3; r++ {
+		for c := 0; c < 3; c++ {
+			m[r][c] += other[r][c]
+		}
+	}
+
+	return m
+}
+
+func (m Mat3) Scale(n float64) Mat3 {
+	for r := 0; r < 3; r++ {
+		for c := 0; c < 3; c++ {
+			m[r][c] *= n
+		}
+	}
+
+	return m
+}
+
+func (m Mat3) Transpose() Mat3 {
+	result := Mat3{}
+
+	for r := 0; r < 3; r++ {
+		for c := 0; c < 3; c++ {
+			result[r][c] = m[c][r]
+		}
+	}
+
+	return result
+}
+
+func (m Mat3) Mul(other Mat3) Mat3 {
+	result := Mat3{}
+
+	for r := 0; r < 3; r++ {
+		for c := 0; c < 3; c++ {
+			for k := 0; k < 3; k++ {
+				result[r][c] += m[r][k] * other[k][c]
+			}
+		}
+	}
+
+	return result
+}
+
+func (m Mat3) MulVec3(v [3]float64) [3]float64 {
+	result := [3]float64{}
+
+	for r := 0; r < 3; r++ {
+		result[r] = m[r][0]*v[0] + m[r][1]*v[1] + m[r][2]*v[2]
+	}
+
+	return result
+}
+
+// Treats v as a point(x, y, 1), so translation is applied.
+func (m Mat3) MulVec2(v vector.Vec2Face) vector.Vec2Face {
+	return vector.NewVec2(
+		m[0][0]*v.X()+m[0][1]*v.Y()+m[0][2],
+		m[1][0]*v.X()+m[1][1]*v.Y()+m[1][2],
+	)
+}
+
+func (m Mat3) Determinant() float64 {
+	return m[0][0]*(m[1][1]*m[2][2]-m[1][2]*m[2][1]) -
+		m[0][1]*(m[1][0]*m[2][2]-m[1][2]*m[2][0]) +
+		m[0][2]*(m[1][0]*m[2][1]-m[1][1]*m[2][0])
+}
+
+// Returns false when the matrix is singular.
+func (m Mat3) Inverse() (Mat3, bool) {
+	det := m.Determinant()
+
+	if det == 0 {
+		return Mat3{}, false
+	}
+
+	// Transposed matrix of cofactors.
+	adjugate := NewMat3(
+		m[1][1]*m[2][2]-m[1][2]*m[2][1],
+		m[0][2]*m[2][1]-m[0][1]*m[2][2],
+		m[0][1]*m[1][2]-m[0][2]*m[1][1],
+
+		m[1][2]*m[2][0]-m[1][0]*m[2][2],
+		m[0][0]*m[2][2]-m[0][2]*m[2][0],
+		m[0][2]*m[1][0]-m[0][0]*m[1][2],
+
+		m[1][0]*m[2][1]-m[1][1]*m[2][0],
+		m[0][1]*m[2][0]-m[0][0]*m[2][1],
+		m[0][0]*m[1][1]-m[0][1]*m[1][0],
+	)
+
+	return adjugate.Scale(1 / det), true
+}
+
+// Solves m * x = b, returning a zero vector when m is singular.
+func (m Mat3) Solve(b [3]float64) [3]float64 {
+	inverse, ok := m.Inverse()
+
+	if !ok {
+		return [3]float64{}
+	}
+
+	return inverse.MulVec3(b)
+}
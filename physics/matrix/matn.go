@@ -0,0 +1,146 @@
+package matrix
+
+import (
+	"math"
+
+	"github.com/kainn9/tteokbokki/vector"
+)
+
+// Row major matrix of any size, mostly used for Jacobians where each
+// row is a constraint and each column a body velocity component.
+type MatN struct {
+	rows, cols int
+	data       []float64
+}
+
+func NewMatN(rows, cols int) MatN {
+	return MatN{
+		rows: rows,
+		cols: cols,
+		data: make([]float64, rows*cols),
+	}
+}
+
+func (m MatN) Rows() int {
+	return m.rows
+}
+
+func (m MatN) Cols() int {
+	return m.cols
+}
+
+func (m MatN) At(row, col int) float64 {
+	return m.data[row*m.cols+col]
+}
+
+func (m MatN) Set(row, col int, v float64) {
+	m.data[row*m.cols+col] = v
+}
+
+// Panics if len(values) does not match the column count.
+func (m MatN) SetRow(row int, values ...float64) {
+	if m.cols != len(values) {
+		panic("matrix: row length does not match the column count")
+	}
+
+	copy(m.data[row*m.cols:(row+1)*m.cols], values)
+}
+
+func (m MatN) Row(row int) []float64 {
+	return m.data[row*m.cols : (row+1)*m.cols]
+}
+
+func (m MatN) Zero() {
+	for i := range m.data {
+		m.data[i] = 0
+	}
+}
+
+func (m MatN) Transpose() MatN {
+	result := NewMatN(m.cols, m.rows)
+
+	for r := 0; r < m.rows; r++ {
+		for c := 0; c < m.cols; c++ {
+			result.Set(c, r, m.At(r, c))
+		}
+	}
+
+	return result
+}
+
+// Panics if the inner dimensions do not match.
+func (m MatN) Mul(other MatN) MatN {
+	if m.cols != other.rows {
+		panic("matrix: cannot multiply mismatched dimensions")
+	}
+
+	result := NewMatN(m.rows, other.cols)
+
+	for r := 0; r < m.rows; r++ {
+		for c := 0; c < other.cols; c++ {
+			sum := 0.0
+
+			for k := 0; k < m.cols; k++ {
+				sum += m.At(r, k) * other.At(k, c)
+			}
+
+			result.Set(r, c, sum)
+		}
+	}
+
+	return result
+}
+
+// Panics if len(v) does not match the column count.
+func (m MatN) MulVec(v []float64) []float64 {
+	if m.cols != len(v) {
+		panic("matrix: cannot multiply mismatched dimensions")
+	}
+
+	result := make([]float64, m.rows)
+
+	for r := 0; r < m.rows; r++ {
+		result[r] = dot(m.Row(r), v)
+	}
+
+	return result
+}
+
+// Only valid for matrices with 2 columns.
+func (m MatN) MulVec2(v vector.Vec2Face) []float64 {
+	return m.MulVec([]float64{v.X(), v.Y()})
+}
+
+// Iteratively solves a * x = b, a must be square. Rows with a
+// zero on the diagonal are skipped and stay at zero.
+func GaussSeidel(a MatN, b []float64, iterations int) []float64 {
+	x := make([]float64, len(b))
+
+	for iter := 0; iter < iterations; iter++ {
+		for i := range b {
+			diagonal := a.At(i, i)
+
+			if diagonal == 0 {
+				continue
+			}
+
+			dx := (b[i] - dot(a.Row(i), x)) / diagonal
+
+			if !math.IsNaN(dx) {
+				x[i] += dx
+			}
+		}
+	}
+
+	return x
+}
+
+func dot(a, b []float64) float64 {
+	sum := 0.0
+
+	for i := range a {
+		sum += a[i] * b[i]
+	}
+
+	return sum
+}
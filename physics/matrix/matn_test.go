@@ -0,0 +1,48 @@
+package matrix
+
+import (
+	"math"
+	"testing"
+)
+
+func TestSetRowPanicsOnWrongLength(t *testing.T) {
+	for _, values := range [][]float64{{1, 2}, {1, 2, 3, 4}} {
+		func() {
+			defer func() {
+				if recover() == nil {
+					t.Errorf("expected SetRow to panic for %d values in a 3 column matrix", len(values))
+				}
+			}()
+
+			NewMatN(2, 3).SetRow(0, values...)
+		}()
+	}
+}
+
+func TestGaussSeidelSolvesSystem(t *testing.T) {
+	a := NewMatN(3, 3)
+	a.SetRow(0, 4, 1, 0)
+	a.SetRow(1, 1, 4, 1)
+	a.SetRow(2, 0, 1, 4)
+
+	expected := []float64{1, -2, 3}
+	x := GaussSeidel(a, a.MulVec(expected), 20)
+
+	for i := range expected {
+		if math.Abs(x[i]-expected[i]) > 1e-9 {
+			t.Errorf("expected x[%d] to be %.0f, got %.12f", i, expected[i], x[i])
+		}
+	}
+}
+
+func TestGaussSeidelSkipsZeroDiagonal(t *testing.T) {
+	a := NewMatN(2, 2)
+	a.SetRow(0, 2, 0)
+	a.SetRow(1, 1, 0)
+
+	x := GaussSeidel(a, []float64{4, 5}, 10)
+
+	if x[0] != 2 || x[1] != 0 {
+		t.Errorf("expected [2 0], got %v", x)
+	}
+}
@@ -7,8 +7,10 @@ import (
 	"github.com/kainn9/tteokbokki/vector"
 )
 
-// Forces.
+// Forces and impulses wake sleeping bodies they act on.
 func AddForce(phys physics_components.PhysicsFace, force vector.Vec2Face) {
+	wake(phys, force.X() != 0 || force.Y() != 0)
+
 	phys.SetSumForces(
 		phys.SumForces().Add(force),
 	)
@@ -20,6 +22,8 @@ func ClearForces(phys physics_components.PhysicsFace) {
 }
 
 func AddTorque(torque float64, phys physics_components.PhysicsFace) {
+	wake(phys, torque != 0)
+
 	phys.SetSumTorque(
 		phys.SumTorque() + torque,
 	)
@@ -30,16 +34,45 @@ func ClearTorque(phys physics_components.PhysicsFace) {
 }
 
 // Impulses.
+// Bodies with infinite mass are never written to, so islands sharing
+// a static body can be solved concurrently.
 func ApplyImpulse(phys physics_components.PhysicsFace, linearImpulse, collisionDisplacement vector.Vec2Face) {
+	wake(phys, linearImpulse.X() != 0 || linearImpulse.Y() != 0)
 
-	linearImpulseScaled := linearImpulse.Scale(phys.InverseMass())
+	if phys.InverseMass() != 0 {
+		linearImpulseScaled := linearImpulse.Scale(phys.InverseMass())
+
+		phys.SetVel(phys.Vel().Add(linearImpulseScaled))
+	}
 
-	phys.SetVel(phys.Vel().Add(linearImpulseScaled))
+	if phys.InverseAngularMass() != 0 {
+		angularImpulseScaled := collisionDisplacement.CrossProduct(linearImpulse) * phys.InverseAngularMass()
+
+		phys.SetAngularVel(
+			phys.AngularVel() + angularImpulseScaled,
+		)
+	}
+}
+
+func ApplyLinearImpulse(phys physics_components.PhysicsFace, linearImpulse vector.Vec2Face) {
+	wake(phys, linearImpulse.X() != 0 || linearImpulse.Y() != 0)
+
+	if phys.InverseMass() == 0 {
+		return
+	}
+
+	phys.SetVel(phys.Vel().Add(linearImpulse.Scale(phys.InverseMass())))
+}
 
-	angularImpulseScaled := collisionDisplacement.CrossProduct(linearImpulse) * phys.InverseAngularMass()
+func ApplyAngularImpulse(phys physics_components.PhysicsFace, angularImpulse float64) {
+	wake(phys, angularImpulse != 0)
+
+	if phys.InverseAngularMass() == 0 {
+		return
+	}
 
 	phys.SetAngularVel(
-		phys.AngularVel() + angularImpulseScaled,
+		phys.AngularVel() + angularImpulse*phys.InverseAngularMass(),
 	)
 }
 
@@ -72,16 +105,49 @@ func Integrate(
 	particleOrBody entitysubset.ParticleFace,
 	dt float64,
 ) {
-	integrateLinear(particleOrBody, dt)
+	IntegrateForces(particleOrBody, dt)
+	IntegrateVelocities(particleOrBody, dt)
+}
+
+// Applies the summed forces/torque to the velocities without moving
+// anything, so constraints can be solved before positions change.
+// Only dynamic bodies are affected by forces.
+func IntegrateForces(
+	particleOrBody entitysubset.ParticleFace,
+	dt float64,
+) {
+	if particleOrBody.BodyType() != physics_components.DynamicBody || !particleOrBody.Awake() {
+		ClearForces(particleOrBody)
+		ClearTorque(particleOrBody)
+		return
+	}
+
+	integrateLinearForces(particleOrBody, dt)
 
 	if body, ok := particleOrBody.(entitysubset.RigidBodyFace); ok {
-		integrateAngular(body, dt)
-		body.UpdateWorldVertices()
+		integrateAngularForces(body, dt)
+	}
+}
+
+// Moves/rotates using the current velocities, static and sleeping
+// bodies never move.
+func IntegrateVelocities(
+	particleOrBody entitysubset.ParticleFace,
+	dt float64,
+) {
+	if particleOrBody.BodyType() == physics_components.StaticBody || !particleOrBody.Awake() {
+		return
 	}
 
+	integrateLinearVelocity(particleOrBody, dt)
+
+	if body, ok := particleOrBody.(entitysubset.RigidBodyFace); ok {
+		integrateAngularVelocity(body, dt)
+		body.UpdateWorldVertices()
+	}
 }
 
-func integrateLinear(
+func integrateLinearForces(
 	particle entitysubset.ParticleFace,
 	dt float64,
 ) {
@@ -93,14 +159,19 @@ func integrateLinear(
 		particle.Vel().Add(particle.Accel().Scale(dt)),
 	)
 
+	ClearForces(particle)
+}
+
+func integrateLinearVelocity(
+	particle entitysubset.ParticleFace,
+	dt float64,
+) {
 	newPos := particle.Position().Add(particle.Vel().Scale(dt))
 
 	particle.SetPosition(newPos)
-
-	ClearForces(particle)
 }
 
-func integrateAngular(body entitysubset.RigidBodyFace, dt float64) {
+func integrateAngularForces(body entitysubset.RigidBodyFace, dt float64) {
 	body.SetAngularAccel(
 		body.SumTorque() * body.InverseAngularMass(),
 	)
@@ -109,9 +180,26 @@ func integrateAngular(body entitysubset.RigidBodyFace, dt float64) {
 		body.AngularVel() + (body.AngularAccel() * dt),
 	)
 
+	ClearTorque(body)
+}
+
+func integrateAngularVelocity(body entitysubset.RigidBodyFace, dt float64) {
 	body.SetRotation(
 		body.Rotation() + (body.AngularVel() * dt),
 	)
+}
 
-	ClearTorque(body)
+// Only wakes bodies that can actually be moved by what woke them,
+// bodies passed through a collision decorator report sleeping bodies
+// as having infinite mass so the solver can not wake them.
+func wake(phys physics_components.PhysicsFace, nonZero bool) {
+	if phys.Awake() || !nonZero || phys.BodyType() != physics_components.DynamicBody {
+		return
+	}
+
+	if phys.InverseMass() == 0 && phys.InverseAngularMass() == 0 {
+		return
+	}
+
+	phys.SetAwake(true)
 }
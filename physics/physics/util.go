@@ -13,3 +13,7 @@ func (*util) IsStaticLinear(phys physics_components.PhysicsFace) bool {
 func (*util) IsStaticAngular(phys physics_components.PhysicsFace) bool {
 	return phys.InverseAngularMass() == 0
 }
+
+func (*util) IsKinematic(phys physics_components.PhysicsFace) bool {
+	return phys.BodyType() == physics_components.KinematicBody
+}
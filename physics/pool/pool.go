@@ -0,0 +1,119 @@
+package pool
+
+import (
+	"runtime"
+	"sync"
+)
+
+// Fixed set of goroutines for splitting loops into chunks. Callers
+// write results into slots indexed by i, so the outcome never depends
+// on how many workers there are or the order they finish in.
+//
+// Whoever creates a pool owns it and has to Close it, a world given one
+// with SetWorkerPool only borrows it and never closes it, so one pool can
+// be shared by several worlds stepped one after another. ForEach only
+// allocates a handful of chunks per call, but it only spreads the work
+// out, the Vec2Face math inside fn still allocates on every op.
+type WorkerPoolFace interface {
+	// Calls fn for every i in [0, n), blocking until all calls return.
+	// fn must not touch state shared with other indices.
+	ForEach(n int, fn func(i int))
+
+	Workers() int
+	// Stops the goroutines, the pool can not be used afterwards.
+	Close()
+}
+
+type WorkerPool struct {
+	workers int
+	tasks   chan task
+
+	// Held for reading while ForEach runs, so Close can not close
+	// tasks under it.
+	mu     sync.RWMutex
+	closed bool
+}
+
+type task struct {
+	start, end int
+	fn         func(i int)
+	done       *sync.WaitGroup
+}
+
+// Fewer than 1 workers uses one per CPU.
+func NewWorkerPool(workers int) WorkerPoolFace {
+	if workers < 1 {
+		workers = runtime.NumCPU()
+	}
+
+	wp := &WorkerPool{
+		workers: workers,
+		tasks:   make(chan task, workers),
+	}
+
+	for i := 0; i < workers; i++ {
+		go wp.work()
+	}
+
+	return wp
+}
+
+func (wp *WorkerPool) ForEach(n int, fn func(i int)) {
+	wp.mu.RLock()
+	defer wp.mu.RUnlock()
+
+	// Not worth waking the workers for.
+	if n <= 1 || wp.workers == 1 || wp.closed {
+		for i := 0; i < n; i++ {
+			fn(i)
+		}
+		return
+	}
+
+	// A few chunks per worker evens out uneven work.
+	chunks := wp.workers * 4
+	if chunks > n {
+		chunks = n
+	}
+	size := (n + chunks - 1) / chunks
+
+	var done sync.WaitGroup
+
+	for start := 0; start < n; start += size {
+		end := start + size
+		if end > n {
+			end = n
+		}
+
+		done.Add(1)
+		wp.tasks <- task{start, end, fn, &done}
+	}
+
+	done.Wait()
+}
+
+func (wp *WorkerPool) Workers() int {
+	return wp.workers
+}
+
+func (wp *WorkerPool) Close() {
+	wp.mu.Lock()
+	defer wp.mu.Unlock()
+
+	if wp.closed {
+		return
+	}
+
+	wp.closed = true
+	close(wp.tasks)
+}
+
+func (wp *WorkerPool) work() {
+	for t := range wp.tasks {
+		for i := t.start; i < t.end; i++ {
+			t.fn(i)
+		}
+
+		t.done.Done()
+	}
+}
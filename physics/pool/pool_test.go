@@ -0,0 +1,54 @@
+package pool
+
+import (
+	"sync"
+	"testing"
+)
+
+func TestForEachVisitsEveryIndex(t *testing.T) {
+	wp := NewWorkerPool(4)
+	defer wp.Close()
+
+	seen := make([]int, 1000)
+	wp.ForEach(len(seen), func(i int) {
+		seen[i]++
+	})
+
+	for i, count := range seen {
+		if count != 1 {
+			t.Fatalf("index %d visited %d times", i, count)
+		}
+	}
+}
+
+func TestCloseWhileRunning(t *testing.T) {
+	wp := NewWorkerPool(4)
+
+	var running sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		running.Add(1)
+		go func() {
+			defer running.Done()
+
+			for j := 0; j < 100; j++ {
+				out := make([]int, 64)
+				wp.ForEach(len(out), func(i int) {
+					out[i] = i
+				})
+			}
+		}()
+	}
+
+	wp.Close()
+	running.Wait()
+
+	// A closed pool falls back to running on the caller.
+	count := 0
+	wp.ForEach(10, func(i int) {
+		count++
+	})
+
+	if count != 10 {
+		t.Errorf("expected 10 calls after closing, got %d", count)
+	}
+}
@@ -1,9 +1,12 @@
 package resolver
 
 import (
+	"math"
+
 	physics_components "github.com/kainn9/tteokbokki/physics/components"
 	"github.com/kainn9/tteokbokki/physics/decorators"
 	entitysubset "github.com/kainn9/tteokbokki/physics/entity_subset"
+	"github.com/kainn9/tteokbokki/physics/matrix"
 
 	"github.com/kainn9/tteokbokki/physics/physics"
 	"github.com/kainn9/tteokbokki/vector"
@@ -11,6 +14,15 @@ import (
 	transform_components "github.com/kainn9/tteokbokki/transform/components"
 )
 
+type resolverConfig struct {
+	// Passes over a manifolds contacts when resolving it.
+	ITERATIONS int
+}
+
+var Config = &resolverConfig{
+	ITERATIONS: 10,
+}
+
 func HandleCollision_Multi(
 	collision *physics_components.Collision,
 
@@ -34,15 +46,254 @@ func HandleCollision(
 	decoratedA := decorators.NewCollisionRigidBodyDecorator(bodyA)
 	decoratedB := decorators.NewCollisionRigidBodyDecorator(bodyB)
 
+	friction, elasticity := Util.MixedMaterial(bodyA, bodyB)
+
 	resolveWithProjection(collision, decoratedA, decoratedB)
-	resolveWithImpulse(collision, decoratedA, decoratedB)
+	resolveWithImpulse(collision, decoratedA, decoratedB, friction, elasticity)
+}
+
+func HandleManifold_Multi(
+	manifold *physics_components.Manifold,
+
+	transA, transB transform_components.TransformFace,
+	shapeA, shapeB transform_components.ShapeFace,
+	physA, physB physics_components.PhysicsFace,
+) {
+	bodyA := entitysubset.NewRigidBody(transA, shapeA, physA)
+	bodyB := entitysubset.NewRigidBody(transB, shapeB, physB)
+
+	HandleManifold(manifold, bodyA, bodyB)
+}
+
+// Projects once using the deepest contact, then solves the impulses of
+// every contact point in the manifold together.
+func HandleManifold(
+	manifold *physics_components.Manifold,
+	bodyA, bodyB entitysubset.RigidBodyFace,
+) {
+	friction, elasticity := Util.MixedMaterial(bodyA, bodyB)
+
+	HandleManifoldWithMaterial(manifold, bodyA, bodyB, friction, elasticity)
+}
+
+// Like HandleManifold, but uses the given friction/elasticity instead
+// of the bodies averages. Returns the impulse applied along the normal
+// and tangent at each contact point.
+func HandleManifoldWithMaterial(
+	manifold *physics_components.Manifold,
+	bodyA, bodyB entitysubset.RigidBodyFace,
+	friction, elasticity float64,
+) (normalImpulses, tangentImpulses []float64) {
+	normalImpulses = make([]float64, len(manifold.Contacts))
+	tangentImpulses = make([]float64, len(manifold.Contacts))
+
+	if bodyCannotMoveOrRotate(bodyA) && bodyCannotMoveOrRotate(bodyB) {
+		return normalImpulses, tangentImpulses
+	}
+	decoratedA := decorators.NewCollisionRigidBodyDecorator(bodyA)
+	decoratedB := decorators.NewCollisionRigidBodyDecorator(bodyB)
+
+	resolveWithProjection(manifold.Deepest(), decoratedA, decoratedB)
+
+	contacts := make([]*manifoldContact, len(manifold.Contacts))
+	for i, collision := range manifold.Contacts {
+		contacts[i] = newManifoldContact(collision, decoratedA, decoratedB, elasticity)
+	}
+
+	// Each contact sees what the others already did, so together they
+	// stop the whole approach instead of each stopping a share of it.
+	for i := 0; i < Config.ITERATIONS; i++ {
+		for _, contact := range contacts {
+			contact.solveFriction(decoratedA, decoratedB, friction)
+		}
+
+		if len(contacts) == 2 {
+			solveNormalBlock(contacts[0], contacts[1], decoratedA, decoratedB)
+			continue
+		}
+
+		for _, contact := range contacts {
+			contact.solveNormal(decoratedA, decoratedB)
+		}
+	}
+
+	for i, contact := range contacts {
+		normalImpulses[i] = contact.normalImpulse
+		tangentImpulses[i] = contact.tangentImpulse
+	}
+
+	return normalImpulses, tangentImpulses
+}
+
+// A point of a manifold being resolved, impulses are accumulated over
+// the iterations and positive when they push the bodies apart.
+type manifoldContact struct {
+	relativePositionA, relativePositionB vector.Vec2Face
+	normal, tangent                      vector.Vec2Face
+
+	normalMass, tangentMass float64
+	// Normal speed the contact should separate at.
+	bounce float64
+
+	normalImpulse, tangentImpulse float64
+}
+
+func newManifoldContact(
+	collision *physics_components.Collision,
+	bodyA, bodyB decorators.CollisionRigidBodyDecoratorFace,
+	elasticity float64,
+) *manifoldContact {
+	contact := &manifoldContact{
+		relativePositionA: collision.End.Sub(bodyA.Position()),
+		relativePositionB: collision.Start.Sub(bodyB.Position()),
+		normal:            collision.Normal,
+		tangent:           collision.Normal.Perpendicular().Norm(),
+	}
+
+	contact.normalMass = contact.mass(bodyA, bodyB, contact.normal)
+	contact.tangentMass = contact.mass(bodyA, bodyB, contact.tangent)
+
+	if approachSpeed := contact.relativeVelocity(bodyA, bodyB).ScalarProduct(contact.normal); approachSpeed > 0 {
+		contact.bounce = elasticity * approachSpeed
+	}
+
+	return contact
+}
+
+func (c *manifoldContact) solveFriction(
+	bodyA, bodyB decorators.CollisionRigidBodyDecoratorFace,
+	friction float64,
+) {
+	// Clamped by the normal impulse so far.
+	tangentSpeed := c.relativeVelocity(bodyA, bodyB).ScalarProduct(c.tangent)
+	maxFriction := friction * c.normalImpulse
+
+	oldTangent := c.tangentImpulse
+	c.tangentImpulse = math.Max(math.Min(oldTangent+tangentSpeed*c.tangentMass, maxFriction), -maxFriction)
+	c.applyImpulse(bodyA, bodyB, c.tangent, c.tangentImpulse-oldTangent)
+}
+
+func (c *manifoldContact) solveNormal(bodyA, bodyB decorators.CollisionRigidBodyDecoratorFace) {
+	// The normal impulse can only ever push the bodies apart.
+	normalSpeed := c.relativeVelocity(bodyA, bodyB).ScalarProduct(c.normal)
+
+	oldNormal := c.normalImpulse
+	c.normalImpulse = math.Max(oldNormal+(normalSpeed+c.bounce)*c.normalMass, 0)
+	c.applyImpulse(bodyA, bodyB, c.normal, c.normalImpulse-oldNormal)
+}
+
+// Solves both normal impulses at once, one at a time they take many
+// passes to agree when pushing on one point spins the body into the other.
+func solveNormalBlock(first, second *manifoldContact, bodyA, bodyB decorators.CollisionRigidBodyDecoratorFace) {
+	k := normalBlockMass(first, second, bodyA, bodyB)
+
+	// Nearly the same point twice, which the block can not tell apart.
+	if k[0][0]*k[0][0] >= maxBlockCondition*k.Determinant() {
+		first.solveNormal(bodyA, bodyB)
+		second.solveNormal(bodyA, bodyB)
+		return
+	}
+
+	oldImpulse := vector.NewVec2(first.normalImpulse, second.normalImpulse)
+	speeds := vector.NewVec2(
+		first.relativeVelocity(bodyA, bodyB).ScalarProduct(first.normal)+first.bounce,
+		second.relativeVelocity(bodyA, bodyB).ScalarProduct(second.normal)+second.bounce,
+	)
+
+	impulse := blockImpulse(k, speeds.Add(k.MulVec2(oldImpulse)))
+
+	first.normalImpulse, second.normalImpulse = impulse.X(), impulse.Y()
+	first.applyImpulse(bodyA, bodyB, first.normal, impulse.X()-oldImpulse.X())
+	second.applyImpulse(bodyA, bodyB, second.normal, impulse.Y()-oldImpulse.Y())
+}
+
+// Above this the block is too close to singular to trust.
+const maxBlockCondition = 1000.0
+
+// How an impulse at either contact changes the normal speed at both.
+func normalBlockMass(first, second *manifoldContact, bodyA, bodyB decorators.CollisionRigidBodyDecoratorFace) matrix.Mat2 {
+	crossA1 := first.relativePositionA.CrossProduct(first.normal)
+	crossB1 := first.relativePositionB.CrossProduct(first.normal)
+	crossA2 := second.relativePositionA.CrossProduct(second.normal)
+	crossB2 := second.relativePositionB.CrossProduct(second.normal)
+
+	inverseMass := bodyA.InverseMass() + bodyB.InverseMass()
+	inverseAngularMassA, inverseAngularMassB := bodyA.InverseAngularMass(), bodyB.InverseAngularMass()
+
+	k11 := inverseMass + crossA1*crossA1*inverseAngularMassA + crossB1*crossB1*inverseAngularMassB
+	k22 := inverseMass + crossA2*crossA2*inverseAngularMassA + crossB2*crossB2*inverseAngularMassB
+	k12 := inverseMass + crossA1*crossA2*inverseAngularMassA + crossB1*crossB2*inverseAngularMassB
+
+	return matrix.NewMat2(k11, k12, k12, k22)
+}
+
+// Total impulses for both contacts, neither negative, that leave neither
+// approaching. b is each contacts speed plus the speed its current
+// impulse already took away. Tries both pushing, then each alone.
+func blockImpulse(k matrix.Mat2, b vector.Vec2Face) vector.Vec2Face {
+	if both := k.Solve(b); both.X() >= 0 && both.Y() >= 0 {
+		return both
+	}
+
+	if first := b.X() / k[0][0]; first >= 0 && k[1][0]*first >= b.Y() {
+		return vector.NewVec2(first, 0)
+	}
+
+	if second := b.Y() / k[1][1]; second >= 0 && k[0][1]*second >= b.X() {
+		return vector.NewVec2(0, second)
+	}
+
+	// Already separating at both.
+	return vector.NewVec2(0, 0)
+}
+
+// Velocity of A's point relative to B's, positive along the
+// normal while they approach.
+func (c manifoldContact) relativeVelocity(bodyA, bodyB decorators.CollisionRigidBodyDecoratorFace) vector.Vec2Face {
+	return pointVelocity(bodyA, c.relativePositionA).Sub(pointVelocity(bodyB, c.relativePositionB))
+}
+
+// Inverse of how much an impulse along direction changes the
+// relative speed along it.
+func (c manifoldContact) mass(
+	bodyA, bodyB decorators.CollisionRigidBodyDecoratorFace,
+	direction vector.Vec2Face,
+) float64 {
+	crossA := c.relativePositionA.CrossProduct(direction)
+	crossB := c.relativePositionB.CrossProduct(direction)
+
+	k := bodyA.InverseMass() + bodyB.InverseMass() +
+		crossA*crossA*bodyA.InverseAngularMass() +
+		crossB*crossB*bodyB.InverseAngularMass()
+
+	if k == 0 {
+		return 0
+	}
+
+	return 1 / k
+}
+
+// Pushes A against direction and B along it.
+func (c manifoldContact) applyImpulse(
+	bodyA, bodyB decorators.CollisionRigidBodyDecoratorFace,
+	direction vector.Vec2Face,
+	lambda float64,
+) {
+	if lambda == 0 {
+		return
+	}
+
+	impulse := direction.Scale(lambda)
+
+	physics.ApplyImpulse(bodyA, impulse.Scale(-1), c.relativePositionA)
+	physics.ApplyImpulse(bodyB, impulse, c.relativePositionB)
 }
 
 func bodyCannotMoveOrRotate(body entitysubset.RigidBodyFace) bool {
 	static := physics.Util.IsStaticLinear(body) && physics.Util.IsStaticAngular(body)
 	unstoppable := (body.UnstoppableLinear() && body.UnstoppableAngular())
 
-	return static || unstoppable
+	return static || unstoppable || !body.Awake()
 
 }
 
@@ -69,12 +320,15 @@ func resolveWithProjection(
 func resolveWithImpulse(
 	collision *physics_components.Collision,
 	bodyA, bodyB decorators.CollisionRigidBodyDecoratorFace,
+	friction, elasticity float64,
 ) {
 	linearImpulseA, linearImpulseB, collisionDisplacementA, collisionDisplacementB :=
 		calculateResolutionImpulses(
 			collision,
 			bodyA,
 			bodyB,
+			friction,
+			elasticity,
 		)
 
 	physics.ApplyImpulse(bodyA, linearImpulseA, collisionDisplacementA)
@@ -85,6 +339,7 @@ func resolveWithImpulse(
 func calculateResolutionImpulses(
 	collision *physics_components.Collision,
 	bodyA, bodyB decorators.CollisionRigidBodyDecoratorFace,
+	friction, elasticity float64,
 ) (
 	collisionImpulseA,
 	collisionImpulseB,
@@ -92,10 +347,6 @@ func calculateResolutionImpulses(
 	collisionDisplacementB vector.Vec2Face,
 ) {
 
-	// Calculate average elasticity and friction.
-	elasticity := (bodyA.Elasticity() + bodyB.Elasticity()) / 2
-	friction := (bodyA.Friction() + bodyB.Friction()) / 2
-
 	// Calculate relative positions of body A and body B.
 	relativePositionA := collision.End.Sub(bodyA.Position())
 	relativePositionB := collision.Start.Sub(bodyB.Position())
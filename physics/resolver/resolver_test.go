@@ -0,0 +1,72 @@
+package resolver
+
+import (
+	"testing"
+
+	"github.com/kainn9/tteokbokki/physics/detector"
+	entitysubset "github.com/kainn9/tteokbokki/physics/entity_subset"
+	"github.com/kainn9/tteokbokki/physics/factory"
+	"github.com/kainn9/tteokbokki/vector"
+)
+
+func newBox(x, y, width, height, mass float64) entitysubset.RigidBodyFace {
+	trans, shape, phys := factory.Components.NewRigidBodyRectangleComponents(x, y, width, height, mass, 0)
+	body := entitysubset.NewRigidBody(trans, shape, phys)
+
+	if mass != 0 {
+		body.SetAndCalculateAngularMass(body)
+	}
+
+	return body
+}
+
+func TestHandleManifoldStopsEveryContact(t *testing.T) {
+	cases := []struct {
+		name       string
+		box        entitysubset.RigidBodyFace
+		vel        vector.Vec2Face
+		angularVel float64
+	}{
+		// Floor top is at y = 290.
+		{"small fast box", newBox(200, 287.5, 6, 6, 1), vector.NewVec2(0, 9000), 0},
+		{"resting box", newBox(200, 270.1, 40, 40, 1), vector.NewVec2(0, 490.0/60.0), 0},
+		{"sliding box", newBox(200, 270.1, 40, 40, 1), vector.NewVec2(300, 50), 0},
+		{"spinning box", newBox(200, 270.1, 40, 40, 1), vector.NewVec2(0, 50), 2},
+		{"heavy box", newBox(200, 270.1, 40, 40, 20), vector.NewVec2(-40, 200), -1},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			floor := newBox(200, 300, 400, 20, 0)
+			c.box.SetVel(c.vel)
+			c.box.SetAngularVel(c.angularVel)
+
+			isColliding, manifold := detector.CheckManifold(c.box, floor)
+			if !isColliding || len(manifold.Contacts) != 2 {
+				t.Fatal("expected the box to rest flat on the floor")
+			}
+
+			normalImpulses, _ := HandleManifoldWithMaterial(manifold, c.box, floor, 0.5, 0)
+
+			for i, collision := range manifold.Contacts {
+				relativePosition := collision.End.Sub(c.box.Position())
+				approachSpeed := pointVelocityOf(c.box, relativePosition).ScalarProduct(collision.Normal)
+
+				if approachSpeed > 1e-6 {
+					t.Errorf("contact %d still approaching at %.3f", i, approachSpeed)
+				}
+
+				if normalImpulses[i] < 0 {
+					t.Errorf("contact %d pulled the bodies together with %.3f", i, normalImpulses[i])
+				}
+			}
+		})
+	}
+}
+
+func pointVelocityOf(body entitysubset.RigidBodyFace, relativePosition vector.Vec2Face) vector.Vec2Face {
+	return body.Vel().Add(vector.NewVec2(
+		-body.AngularVel()*relativePosition.Y(),
+		body.AngularVel()*relativePosition.X(),
+	))
+}
@@ -4,6 +4,9 @@ import (
 	"math"
 
 	physics_components "github.com/kainn9/tteokbokki/physics/components"
+	"github.com/kainn9/tteokbokki/physics/decorators"
+	entitysubset "github.com/kainn9/tteokbokki/physics/entity_subset"
+	"github.com/kainn9/tteokbokki/vector"
 )
 
 type util struct{}
@@ -18,3 +21,22 @@ func (util) Snap(c *physics_components.Collision) {
 	}
 
 }
+
+// Average friction and elasticity of the pair.
+func (util) MixedMaterial(bodyA, bodyB entitysubset.RigidBodyFace) (friction, elasticity float64) {
+	friction = (bodyA.Friction() + bodyB.Friction()) / 2
+	elasticity = (bodyA.Elasticity() + bodyB.Elasticity()) / 2
+
+	return friction, elasticity
+}
+
+func pointVelocity(
+	body decorators.CollisionRigidBodyDecoratorFace,
+	relativePosition vector.Vec2Face,
+) vector.Vec2Face {
+	return body.Vel().Add(
+		vector.NewVec2(
+			-body.AngularVel()*relativePosition.Y(),
+			body.AngularVel()*relativePosition.X(),
+		))
+}
@@ -0,0 +1,77 @@
+package solver
+
+import (
+	physics_components "github.com/kainn9/tteokbokki/physics/components"
+	entitysubset "github.com/kainn9/tteokbokki/physics/entity_subset"
+)
+
+// Carries the accumulated impulses of each contact over to the next
+// step, so the solver starts close to the answer(warm starting).
+type ContactCacheFace interface {
+	BeginStep()
+
+	NewPenConstraint(
+		collision *physics_components.Collision,
+		bodyA, bodyB entitysubset.RigidBodyFace,
+	) PenConstraintFace
+
+	WarmStarting() bool
+	SetWarmStarting(bool)
+
+	Len() int
+}
+
+type ContactCache struct {
+	previous, current map[contactKey][]float64
+	warmStarting      bool
+}
+
+type contactKey struct {
+	bodyA, bodyB entitysubset.RigidBodyFace
+	id           uint32
+}
+
+func NewContactCache() ContactCacheFace {
+	return &ContactCache{
+		previous:     make(map[contactKey][]float64),
+		current:      make(map[contactKey][]float64),
+		warmStarting: true,
+	}
+}
+
+// Drops contacts that were not seen during the last step.
+func (cc *ContactCache) BeginStep() {
+	cc.previous = cc.current
+	cc.current = make(map[contactKey][]float64, len(cc.previous))
+}
+
+// Builds a pen constraint seeded with the impulses the same contact
+// had last step. The cache keeps a reference to the constraints
+// impulses, so whatever the solver ends with is stored.
+func (cc *ContactCache) NewPenConstraint(
+	collision *physics_components.Collision,
+	bodyA, bodyB entitysubset.RigidBodyFace,
+) PenConstraintFace {
+	pc := NewPenConstraint(collision, bodyA, bodyB).(*penConstraint)
+	key := contactKey{bodyA, bodyB, collision.ID}
+
+	if cached, ok := cc.previous[key]; ok && cc.warmStarting {
+		copy(pc.CachedLambda, cached)
+	}
+
+	cc.current[key] = pc.CachedLambda
+
+	return pc
+}
+
+func (cc ContactCache) WarmStarting() bool {
+	return cc.warmStarting
+}
+
+func (cc *ContactCache) SetWarmStarting(warmStarting bool) {
+	cc.warmStarting = warmStarting
+}
+
+func (cc ContactCache) Len() int {
+	return len(cc.current)
+}
@@ -0,0 +1,97 @@
+package solver
+
+import (
+	"testing"
+
+	physics_components "github.com/kainn9/tteokbokki/physics/components"
+	entitysubset "github.com/kainn9/tteokbokki/physics/entity_subset"
+	"github.com/kainn9/tteokbokki/physics/factory"
+	"github.com/kainn9/tteokbokki/vector"
+)
+
+func newCachePair() (bodyA, bodyB entitysubset.RigidBodyFace) {
+	trans, shape, phys := factory.Components.NewRigidBodyRectangleComponents(100, 100, 20, 20, 0, 0)
+	bodyA = entitysubset.NewRigidBody(trans, shape, phys)
+
+	trans, shape, phys = factory.Components.NewRigidBodyRectangleComponents(100, 80, 20, 20, 1, 0)
+	bodyB = entitysubset.NewRigidBody(trans, shape, phys)
+
+	return bodyA, bodyB
+}
+
+func newCacheCollision(id uint32) *physics_components.Collision {
+	collision := physics_components.NewCollision(
+		vector.NewVec2(100, 90), vector.NewVec2(100, 91), vector.NewVec2(0, -1), 1,
+	)
+	collision.ID = id
+
+	return collision
+}
+
+// Starts a step and builds a constraint for each feature id, returning
+// their impulses for tests to write into the way the solver would.
+func stepCache(cc ContactCacheFace, bodyA, bodyB entitysubset.RigidBodyFace, ids ...uint32) [][]float64 {
+	cc.BeginStep()
+
+	lambdas := [][]float64{}
+	for _, id := range ids {
+		pc := cc.NewPenConstraint(newCacheCollision(id), bodyA, bodyB)
+		lambdas = append(lambdas, pc.(*penConstraint).CachedLambda)
+	}
+
+	return lambdas
+}
+
+func TestContactCacheWarmStartsSameFeature(t *testing.T) {
+	cc := NewContactCache()
+	bodyA, bodyB := newCachePair()
+
+	first := stepCache(cc, bodyA, bodyB, 1, 2)
+	first[0][0] = 3
+	first[0][1] = -1
+	first[1][0] = 5
+
+	second := stepCache(cc, bodyA, bodyB, 1, 3)
+
+	if lambda := second[0]; lambda[0] != 3 || lambda[1] != -1 {
+		t.Errorf("expected the same feature to start at [3 -1], got %v", lambda)
+	}
+
+	if lambda := second[1]; lambda[0] != 0 || lambda[1] != 0 {
+		t.Errorf("expected a new feature to start at zero, got %v", lambda)
+	}
+
+	if cc.Len() != 2 {
+		t.Errorf("expected the feature that stopped touching to be dropped, %d contacts cached", cc.Len())
+	}
+
+	// Feature 2 was not seen last step, so it starts over.
+	third := stepCache(cc, bodyA, bodyB, 2)
+
+	if lambda := third[0]; lambda[0] != 0 {
+		t.Errorf("expected a feature that stopped touching to start over, got %v", lambda)
+	}
+}
+
+func TestContactCacheWarmStartingDisabled(t *testing.T) {
+	cc := NewContactCache()
+	cc.SetWarmStarting(false)
+	bodyA, bodyB := newCachePair()
+
+	first := stepCache(cc, bodyA, bodyB, 1)
+	first[0][0] = 3
+
+	second := stepCache(cc, bodyA, bodyB, 1)
+
+	if lambda := second[0]; lambda[0] != 0 {
+		t.Errorf("expected no warm start while disabled, got %v", lambda)
+	}
+
+	// Still cached, turning it back on picks up where the solver left off.
+	second[0][0] = 4
+	cc.SetWarmStarting(true)
+
+	if third := stepCache(cc, bodyA, bodyB, 1); third[0][0] != 4 {
+		t.Errorf("expected warm starting to resume at 4, got %v", third[0])
+	}
+}
@@ -0,0 +1,225 @@
+package solver
+
+import (
+	"math"
+
+	"github.com/kainn9/tteokbokki/physics/decorators"
+	entitysubset "github.com/kainn9/tteokbokki/physics/entity_subset"
+	"github.com/kainn9/tteokbokki/physics/matrix"
+	"github.com/kainn9/tteokbokki/vector"
+)
+
+// Keeps the anchors a set length apart. With a min/max range the
+// length may vary within it, like a rope, and a frequency turns the
+// length into a spring instead of a rigid rod.
+type DistanceJointFace interface {
+	JointFace
+
+	LocalAnchorA() vector.Vec2Face
+	LocalAnchorB() vector.Vec2Face
+
+	Length() float64
+	SetLength(float64)
+
+	MinLength() float64
+	MaxLength() float64
+	// Equal min and max(the default) keep the length exact.
+	SetLengthRange(min, max float64)
+
+	Frequency() float64
+	DampingRatio() float64
+	// Zero frequency(the default) is rigid.
+	SetSoftness(frequency, dampingRatio float64)
+
+	CurrentLength() float64
+}
+
+type DistanceJoint struct {
+	joint
+
+	length, minLength, maxLength float64
+	frequency, dampingRatio      float64
+
+	// Refreshed by PreSolve.
+	decoratedA, decoratedB      decorators.CollisionRigidBodyDecoratorFace
+	jacobian, jacobianTranspose matrix.MatN
+	currentLength               float64
+	mass, dt                    float64
+	gamma, bias                 float64
+
+	// Accumulated impulses, carried over for warm starting.
+	impulse, lowerImpulse, upperImpulse float64
+}
+
+// Anchors are local to each body, the length starts out as their
+// current distance apart.
+func NewDistanceJoint(
+	bodyA, bodyB entitysubset.RigidBodyFace,
+	localAnchorA, localAnchorB vector.Vec2Face,
+) DistanceJointFace {
+	dj := &DistanceJoint{
+		joint: joint{
+			bodyA:        bodyA,
+			bodyB:        bodyB,
+			localAnchorA: localAnchorA,
+			localAnchorB: localAnchorB,
+		},
+		jacobian: matrix.NewMatN(1, 6),
+	}
+
+	dj.SetLength(dj.anchorDelta().Mag())
+
+	return dj
+}
+
+func (dj DistanceJoint) Length() float64 {
+	return dj.length
+}
+
+// Also resets the range to the new length.
+func (dj *DistanceJoint) SetLength(length float64) {
+	dj.length = length
+	dj.minLength = length
+	dj.maxLength = length
+}
+
+func (dj DistanceJoint) MinLength() float64 {
+	return dj.minLength
+}
+
+func (dj DistanceJoint) MaxLength() float64 {
+	return dj.maxLength
+}
+
+func (dj *DistanceJoint) SetLengthRange(min, max float64) {
+	dj.minLength = math.Min(min, max)
+	dj.maxLength = math.Max(min, max)
+	dj.length = clamp(dj.length, dj.minLength, dj.maxLength)
+}
+
+func (dj DistanceJoint) Frequency() float64 {
+	return dj.frequency
+}
+
+func (dj DistanceJoint) DampingRatio() float64 {
+	return dj.dampingRatio
+}
+
+func (dj *DistanceJoint) SetSoftness(frequency, dampingRatio float64) {
+	dj.frequency = frequency
+	dj.dampingRatio = dampingRatio
+}
+
+func (dj DistanceJoint) CurrentLength() float64 {
+	return dj.anchorDelta().Mag()
+}
+
+func (dj *DistanceJoint) PreSolve(dt float64) {
+	dj.decoratedA, dj.decoratedB = dj.decorated()
+	dj.dt = dt
+
+	relativeA, relativeB := dj.relativeAnchors()
+	delta := dj.anchorDelta()
+
+	dj.currentLength = delta.Mag()
+
+	// Anchors on top of each other have no direction to push in.
+	var axis vector.Vec2Face = vector.NewVec2(0, 0)
+	if dj.currentLength > 0 {
+		axis = delta.Scale(1 / dj.currentLength)
+	}
+
+	dj.jacobian.SetRow(0,
+		-axis.X(), -axis.Y(), -relativeA.CrossProduct(axis),
+		axis.X(), axis.Y(), relativeB.CrossProduct(axis),
+	)
+	dj.jacobianTranspose = dj.jacobian.Transpose()
+
+	lhs := dj.jacobian.Mul(inverseMassMatrix(dj.decoratedA, dj.decoratedB)).Mul(dj.jacobianTranspose)
+	dj.mass = invertOrZero(lhs.At(0, 0))
+
+	// Rigid joints only have the equality row, ranged ones only use it
+	// when they are springy.
+	C := dj.currentLength - dj.length
+	gamma, biasFactor := softness(dj.frequency, dj.dampingRatio, dj.mass, dt)
+
+	dj.gamma = gamma
+	dj.bias = C * biasFactor
+
+	if gamma == 0 {
+		dj.bias = (Config.BAUMGARTE / dt) * C
+	}
+
+	if dj.isRanged() && dj.frequency <= 0 {
+		dj.impulse = 0
+	}
+
+	if !dj.isRanged() {
+		dj.lowerImpulse, dj.upperImpulse = 0, 0
+	}
+
+	dj.applyImpulse(dj.impulse + dj.lowerImpulse - dj.upperImpulse)
+}
+
+func (dj *DistanceJoint) Solve() {
+	if !dj.isRanged() || dj.frequency > 0 {
+		springMass := dj.mass
+		if dj.gamma > 0 {
+			springMass = invertOrZero(invertOrZero(dj.mass) + dj.gamma)
+		}
+
+		lambda := -springMass * (dj.speed() + dj.bias + dj.gamma*dj.impulse)
+		dj.impulse += lambda
+		dj.applyImpulse(lambda)
+	}
+
+	if !dj.isRanged() {
+		return
+	}
+
+	// Lower limit can only push the anchors apart.
+	lowerC := dj.currentLength - dj.minLength
+	lambda := -dj.mass * (dj.speed() + limitBias(lowerC, dj.dt))
+
+	oldLower := dj.lowerImpulse
+	dj.lowerImpulse = math.Max(oldLower+lambda, 0)
+	dj.applyImpulse(dj.lowerImpulse - oldLower)
+
+	// Upper limit can only pull them together.
+	upperC := dj.maxLength - dj.currentLength
+	lambda = -dj.mass * (-dj.speed() + limitBias(upperC, dj.dt))
+
+	oldUpper := dj.upperImpulse
+	dj.upperImpulse = math.Max(oldUpper+lambda, 0)
+	dj.applyImpulse(-(dj.upperImpulse - oldUpper))
+}
+
+func (dj *DistanceJoint) PostSolve() {}
+
+func (dj DistanceJoint) isRanged() bool {
+	return dj.minLength < dj.maxLength
+}
+
+// Rate the anchors are moving apart.
+func (dj DistanceJoint) speed() float64 {
+	return dj.jacobian.MulVec(velocityVector(dj.decoratedA, dj.decoratedB))[0]
+}
+
+func (dj DistanceJoint) applyImpulse(lambda float64) {
+	if lambda == 0 {
+		return
+	}
+
+	impulses := dj.jacobianTranspose.MulVec([]float64{lambda})
+	applyImpulseVector(dj.decoratedA, dj.decoratedB, impulses)
+}
+
+// Limits that are not reached yet let the bodies close the gap within
+// a step(speculative), violated ones are pushed back with Baumgarte.
+func limitBias(C, dt float64) float64 {
+	if C > 0 {
+		return C / dt
+	}
+
+	return (Config.BAUMGARTE / dt) * C
+}
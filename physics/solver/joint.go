@@ -0,0 +1,160 @@
+package solver
+
+import (
+	"math"
+
+	"github.com/kainn9/tteokbokki/physics/decorators"
+	entitysubset "github.com/kainn9/tteokbokki/physics/entity_subset"
+	"github.com/kainn9/tteokbokki/physics/matrix"
+	"github.com/kainn9/tteokbokki/vector"
+)
+
+// Constraint linking two bodies, solved per island alongside contacts.
+// With the world's projection solver joints only fix up velocities and
+// are solved before anything moves, contacts are projected afterwards
+// and can still push jointed bodies apart.
+type JointFace interface {
+	ConstraintFace
+
+	BodyA() entitysubset.RigidBodyFace
+	BodyB() entitysubset.RigidBodyFace
+
+	// Whether the two bodies still collide with each other, false by default.
+	CollideConnected() bool
+	SetCollideConnected(bool)
+}
+
+// Called at the end of the step a joint broke in, after the world has
+// removed it.
+type BreakFunc func(joint JointFace)
+
+// Joints that break under too much load.
+type BreakableJointFace interface {
+	JointFace
+
+	Broken() bool
+
+	OnBreak() BreakFunc
+	SetOnBreak(BreakFunc)
+}
+
+// Shared by every joint, anchors are in each bodies local space.
+type joint struct {
+	bodyA, bodyB               entitysubset.RigidBodyFace
+	localAnchorA, localAnchorB vector.Vec2Face
+	collideConnected           bool
+}
+
+func (j joint) BodyA() entitysubset.RigidBodyFace {
+	return j.bodyA
+}
+
+func (j joint) BodyB() entitysubset.RigidBodyFace {
+	return j.bodyB
+}
+
+func (j joint) CollideConnected() bool {
+	return j.collideConnected
+}
+
+func (j *joint) SetCollideConnected(collideConnected bool) {
+	j.collideConnected = collideConnected
+}
+
+func (j joint) LocalAnchorA() vector.Vec2Face {
+	return j.localAnchorA
+}
+
+func (j joint) LocalAnchorB() vector.Vec2Face {
+	return j.localAnchorB
+}
+
+// Decorated so sleeping/unstoppable bodies act as if they were static.
+func (j joint) decorated() (bodyA, bodyB decorators.CollisionRigidBodyDecoratorFace) {
+	return decorators.NewCollisionRigidBodyDecorator(j.bodyA),
+		decorators.NewCollisionRigidBodyDecorator(j.bodyB)
+}
+
+// Anchors relative to each bodies position, rotated into world space.
+func (j joint) relativeAnchors() (relativeA, relativeB vector.Vec2Face) {
+	return j.localAnchorA.Rotate(j.bodyA.Rotation()),
+		j.localAnchorB.Rotate(j.bodyB.Rotation())
+}
+
+// Turns frequency(hz) and damping ratio into the gamma and bias
+// factor used by soft constraints, for a row with the given mass.
+// Zero frequency returns zeroes, meaning a rigid constraint.
+func softness(frequency, dampingRatio, mass, dt float64) (gamma, biasFactor float64) {
+	if frequency <= 0 || mass == 0 {
+		return 0, 0
+	}
+
+	omega := 2 * math.Pi * frequency
+	damping := 2 * mass * dampingRatio * omega
+	stiffness := mass * omega * omega
+
+	gamma = invertOrZero(dt * (damping + dt*stiffness))
+	biasFactor = dt * stiffness * gamma
+
+	return gamma, biasFactor
+}
+
+// From anchor A to anchor B, in world space.
+func (j joint) anchorDelta() vector.Vec2Face {
+	relativeA, relativeB := j.relativeAnchors()
+
+	return j.bodyB.Position().Add(relativeB).Sub(j.bodyA.Position().Add(relativeA))
+}
+
+// Velocity of a point at relative, from spinning at angularVel.
+func angularToLinear(angularVel float64, relative vector.Vec2Face) vector.Vec2Face {
+	return vector.NewVec2(-angularVel*relative.Y(), angularVel*relative.X())
+}
+
+// Anchor in a bodies local space, from a point in world space.
+func worldToLocal(body entitysubset.RigidBodyFace, worldPoint vector.Vec2Face) vector.Vec2Face {
+	return worldPoint.Sub(body.Position()).Rotate(-body.Rotation())
+}
+
+// Rows keeping the two anchors on top of each other, along x then y.
+func pointJacobian(relativeA, relativeB vector.Vec2Face) matrix.MatN {
+	jacobian := matrix.NewMatN(2, 6)
+
+	jacobian.SetRow(0, -1, 0, relativeA.Y(), 1, 0, -relativeB.Y())
+	jacobian.SetRow(1, 0, -1, -relativeA.X(), 0, 1, relativeB.X())
+
+	return jacobian
+}
+
+// Row for the relative angular velocity of B to A.
+func angularJacobian() matrix.MatN {
+	jacobian := matrix.NewMatN(1, 6)
+	jacobian.SetRow(0, 0, 0, -1, 0, 0, 1)
+
+	return jacobian
+}
+
+// J * M^-1 * J^T, which gets inverted(or solved against) to turn
+// a velocity error into an impulse.
+func effectiveMass(jacobian matrix.MatN, bodyA, bodyB decorators.CollisionRigidBodyDecoratorFace) matrix.MatN {
+	return jacobian.Mul(inverseMassMatrix(bodyA, bodyB)).Mul(jacobian.Transpose())
+}
+
+// Solves two rows together, falling back to solving them one at a time
+// when they depend on each other(e.g. a body with no angular mass).
+func solveBlock(k matrix.Mat2, rhs vector.Vec2Face) vector.Vec2Face {
+	if k.Determinant() != 0 {
+		return k.Solve(rhs)
+	}
+
+	return vector.NewVec2(rhs.X()*invertOrZero(k[0][0]), rhs.Y()*invertOrZero(k[1][1]))
+}
+
+// Applies one impulse per row of jacobian.
+func applyJacobianImpulse(
+	jacobian matrix.MatN,
+	bodyA, bodyB decorators.CollisionRigidBodyDecoratorFace,
+	lambdas ...float64,
+) {
+	applyImpulseVector(bodyA, bodyB, jacobian.Transpose().MulVec(lambdas))
+}
@@ -0,0 +1,268 @@
+package solver
+
+import (
+	"math"
+
+	"github.com/kainn9/tteokbokki/physics/decorators"
+	entitysubset "github.com/kainn9/tteokbokki/physics/entity_subset"
+	"github.com/kainn9/tteokbokki/physics/matrix"
+	"github.com/kainn9/tteokbokki/vector"
+)
+
+// Lets B slide along an axis fixed to A, with their relative rotation
+// locked. The translation can be limited and driven by a motor, both
+// are off by default.
+type PrismaticJointFace interface {
+	JointFace
+
+	LocalAnchorA() vector.Vec2Face
+	LocalAnchorB() vector.Vec2Face
+	LocalAxisA() vector.Vec2Face
+
+	// Relative angle of B to A when the joint was made.
+	ReferenceAngle() float64
+	// Distance between the anchors along the axis.
+	JointTranslation() float64
+	JointSpeed() float64
+
+	LimitEnabled() bool
+	SetLimitEnabled(bool)
+	LowerTranslation() float64
+	UpperTranslation() float64
+	SetLimits(lower, upper float64)
+
+	MotorEnabled() bool
+	SetMotorEnabled(bool)
+	// Target speed along the axis, in pixels per second.
+	MotorSpeed() float64
+	SetMotorSpeed(float64)
+	MaxMotorForce() float64
+	SetMaxMotorForce(float64)
+	// Impulse the motor applied last step.
+	MotorImpulse() float64
+}
+
+type PrismaticJoint struct {
+	joint
+
+	localAxisA     vector.Vec2Face
+	referenceAngle float64
+
+	limitEnabled                       bool
+	lowerTranslation, upperTranslation float64
+
+	motorEnabled  bool
+	motorSpeed    float64
+	maxMotorForce float64
+
+	// Refreshed by PreSolve. Rows of the Jacobian are the axis,
+	// the perpendicular axis and the relative angle.
+	decoratedA, decoratedB decorators.CollisionRigidBodyDecoratorFace
+	jacobian               matrix.MatN
+	axialMass              float64
+	lockMass               matrix.Mat2
+	lockBias               vector.Vec2Face
+	translation, dt        float64
+
+	// Accumulated impulses, carried over for warm starting. The lock
+	// impulse holds the perpendicular and angular rows.
+	lockImpulse                vector.Vec2Face
+	motorImpulse               float64
+	lowerImpulse, upperImpulse float64
+}
+
+// Anchor is in world space, axis is in A's local space.
+func NewPrismaticJoint(
+	bodyA, bodyB entitysubset.RigidBodyFace,
+	anchor, localAxisA vector.Vec2Face,
+) PrismaticJointFace {
+	return &PrismaticJoint{
+		joint: joint{
+			bodyA:        bodyA,
+			bodyB:        bodyB,
+			localAnchorA: worldToLocal(bodyA, anchor),
+			localAnchorB: worldToLocal(bodyB, anchor),
+		},
+		localAxisA:     localAxisA.Norm(),
+		referenceAngle: bodyB.Rotation() - bodyA.Rotation(),
+		jacobian:       matrix.NewMatN(3, 6),
+		lockImpulse:    vector.NewVec2(0, 0),
+	}
+}
+
+func (pj PrismaticJoint) LocalAxisA() vector.Vec2Face {
+	return pj.localAxisA
+}
+
+func (pj PrismaticJoint) ReferenceAngle() float64 {
+	return pj.referenceAngle
+}
+
+func (pj PrismaticJoint) JointTranslation() float64 {
+	return pj.anchorDelta().ScalarProduct(pj.axis())
+}
+
+func (pj PrismaticJoint) JointSpeed() float64 {
+	relativeA, relativeB := pj.relativeAnchors()
+	delta := pj.anchorDelta()
+	axis := pj.axis()
+
+	velA := pj.bodyA.Vel().Add(angularToLinear(pj.bodyA.AngularVel(), relativeA))
+	velB := pj.bodyB.Vel().Add(angularToLinear(pj.bodyB.AngularVel(), relativeB))
+
+	// The axis turns with A, which moves B along it too.
+	return velB.Sub(velA).ScalarProduct(axis) +
+		delta.ScalarProduct(angularToLinear(pj.bodyA.AngularVel(), axis))
+}
+
+func (pj PrismaticJoint) LimitEnabled() bool {
+	return pj.limitEnabled
+}
+
+func (pj *PrismaticJoint) SetLimitEnabled(enabled bool) {
+	pj.limitEnabled = enabled
+}
+
+func (pj PrismaticJoint) LowerTranslation() float64 {
+	return pj.lowerTranslation
+}
+
+func (pj PrismaticJoint) UpperTranslation() float64 {
+	return pj.upperTranslation
+}
+
+// In pixels along the axis, from where the anchors started.
+func (pj *PrismaticJoint) SetLimits(lower, upper float64) {
+	pj.lowerTranslation = math.Min(lower, upper)
+	pj.upperTranslation = math.Max(lower, upper)
+}
+
+func (pj PrismaticJoint) MotorEnabled() bool {
+	return pj.motorEnabled
+}
+
+func (pj *PrismaticJoint) SetMotorEnabled(enabled bool) {
+	pj.motorEnabled = enabled
+}
+
+func (pj PrismaticJoint) MotorSpeed() float64 {
+	return pj.motorSpeed
+}
+
+func (pj *PrismaticJoint) SetMotorSpeed(speed float64) {
+	pj.motorSpeed = speed
+}
+
+func (pj PrismaticJoint) MaxMotorForce() float64 {
+	return pj.maxMotorForce
+}
+
+func (pj *PrismaticJoint) SetMaxMotorForce(force float64) {
+	pj.maxMotorForce = force
+}
+
+func (pj PrismaticJoint) MotorImpulse() float64 {
+	return pj.motorImpulse
+}
+
+func (pj *PrismaticJoint) PreSolve(dt float64) {
+	pj.decoratedA, pj.decoratedB = pj.decorated()
+	pj.dt = dt
+
+	relativeA, relativeB := pj.relativeAnchors()
+	delta := pj.anchorDelta()
+	axis := pj.axis()
+	perp := axis.Perpendicular()
+
+	// A's lever arm reaches all the way to B's anchor, since the axis
+	// is fixed to A.
+	leverA := delta.Add(relativeA)
+
+	pj.jacobian.SetRow(0,
+		-axis.X(), -axis.Y(), -leverA.CrossProduct(axis),
+		axis.X(), axis.Y(), relativeB.CrossProduct(axis),
+	)
+	pj.jacobian.SetRow(1,
+		-perp.X(), -perp.Y(), -leverA.CrossProduct(perp),
+		perp.X(), perp.Y(), relativeB.CrossProduct(perp),
+	)
+	pj.jacobian.SetRow(2, 0, 0, -1, 0, 0, 1)
+
+	k := effectiveMass(pj.jacobian, pj.decoratedA, pj.decoratedB)
+	pj.axialMass = invertOrZero(k.At(0, 0))
+	pj.lockMass = matrix.NewMat2(k.At(1, 1), k.At(1, 2), k.At(2, 1), k.At(2, 2))
+
+	angle := pj.bodyB.Rotation() - pj.bodyA.Rotation() - pj.referenceAngle
+	pj.lockBias = vector.NewVec2(delta.ScalarProduct(perp), angle).Scale(Config.BAUMGARTE / dt)
+
+	pj.translation = delta.ScalarProduct(axis)
+
+	if !pj.motorEnabled {
+		pj.motorImpulse = 0
+	}
+
+	if !pj.limitEnabled {
+		pj.lowerImpulse, pj.upperImpulse = 0, 0
+	}
+
+	// Warm start with the impulses carried over from last step.
+	applyJacobianImpulse(pj.jacobian, pj.decoratedA, pj.decoratedB,
+		pj.motorImpulse+pj.lowerImpulse-pj.upperImpulse,
+		pj.lockImpulse.X(),
+		pj.lockImpulse.Y(),
+	)
+}
+
+func (pj *PrismaticJoint) Solve() {
+	// Motor and limits first, the lock matters most.
+	if pj.motorEnabled {
+		lambda := -pj.axialMass * (pj.speeds()[0] - pj.motorSpeed)
+
+		maxImpulse := pj.maxMotorForce * pj.dt
+		oldImpulse := pj.motorImpulse
+		pj.motorImpulse = clamp(oldImpulse+lambda, -maxImpulse, maxImpulse)
+		pj.applyAxialImpulse(pj.motorImpulse - oldImpulse)
+	}
+
+	if pj.limitEnabled {
+		// Lower limit can only push B forward along the axis.
+		lowerC := pj.translation - pj.lowerTranslation
+		lambda := -pj.axialMass * (pj.speeds()[0] + limitBias(lowerC, pj.dt))
+
+		oldLower := pj.lowerImpulse
+		pj.lowerImpulse = math.Max(oldLower+lambda, 0)
+		pj.applyAxialImpulse(pj.lowerImpulse - oldLower)
+
+		// Upper limit can only push it back.
+		upperC := pj.upperTranslation - pj.translation
+		lambda = -pj.axialMass * (-pj.speeds()[0] + limitBias(upperC, pj.dt))
+
+		oldUpper := pj.upperImpulse
+		pj.upperImpulse = math.Max(oldUpper+lambda, 0)
+		pj.applyAxialImpulse(-(pj.upperImpulse - oldUpper))
+	}
+
+	speeds := pj.speeds()
+	lambda := solveBlock(pj.lockMass, vector.NewVec2(speeds[1], speeds[2]).Add(pj.lockBias).Scale(-1))
+
+	pj.lockImpulse = pj.lockImpulse.Add(lambda)
+	applyJacobianImpulse(pj.jacobian, pj.decoratedA, pj.decoratedB, 0, lambda.X(), lambda.Y())
+}
+
+func (pj *PrismaticJoint) PostSolve() {}
+
+func (pj PrismaticJoint) axis() vector.Vec2Face {
+	return pj.localAxisA.Rotate(pj.bodyA.Rotation())
+}
+
+func (pj PrismaticJoint) speeds() []float64 {
+	return pj.jacobian.MulVec(velocityVector(pj.decoratedA, pj.decoratedB))
+}
+
+func (pj PrismaticJoint) applyAxialImpulse(lambda float64) {
+	if lambda == 0 {
+		return
+	}
+
+	applyJacobianImpulse(pj.jacobian, pj.decoratedA, pj.decoratedB, lambda, 0, 0)
+}
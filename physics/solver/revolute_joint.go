@@ -0,0 +1,229 @@
+package solver
+
+import (
+	"math"
+
+	"github.com/kainn9/tteokbokki/physics/decorators"
+	entitysubset "github.com/kainn9/tteokbokki/physics/entity_subset"
+	"github.com/kainn9/tteokbokki/physics/matrix"
+	"github.com/kainn9/tteokbokki/vector"
+)
+
+// Pins the bodies together at a shared anchor, leaving them free to
+// rotate around it. The relative angle can be limited and driven by a
+// motor, both are off by default.
+type RevoluteJointFace interface {
+	JointFace
+
+	LocalAnchorA() vector.Vec2Face
+	LocalAnchorB() vector.Vec2Face
+
+	// Relative angle of B to A when the joint was made.
+	ReferenceAngle() float64
+	// Relative angle of B to A, minus the reference angle.
+	JointAngle() float64
+	JointSpeed() float64
+
+	LimitEnabled() bool
+	SetLimitEnabled(bool)
+	LowerAngle() float64
+	UpperAngle() float64
+	SetLimits(lower, upper float64)
+
+	MotorEnabled() bool
+	SetMotorEnabled(bool)
+	// Target relative angular speed, in radians per second.
+	MotorSpeed() float64
+	SetMotorSpeed(float64)
+	MaxMotorTorque() float64
+	SetMaxMotorTorque(float64)
+	// Impulse the motor applied last step.
+	MotorImpulse() float64
+}
+
+type RevoluteJoint struct {
+	joint
+
+	referenceAngle float64
+
+	limitEnabled           bool
+	lowerAngle, upperAngle float64
+
+	motorEnabled   bool
+	motorSpeed     float64
+	maxMotorTorque float64
+
+	// Refreshed by PreSolve.
+	decoratedA, decoratedB decorators.CollisionRigidBodyDecoratorFace
+	pointJacobian          matrix.MatN
+	angularJacobian        matrix.MatN
+	pointMass              matrix.Mat2
+	angularMass            float64
+	pointBias              vector.Vec2Face
+	angle, dt              float64
+
+	// Accumulated impulses, carried over for warm starting.
+	pointImpulse               vector.Vec2Face
+	motorImpulse               float64
+	lowerImpulse, upperImpulse float64
+}
+
+// Anchor is in world space, both bodies keep it where it is now.
+func NewRevoluteJoint(bodyA, bodyB entitysubset.RigidBodyFace, anchor vector.Vec2Face) RevoluteJointFace {
+	return &RevoluteJoint{
+		joint: joint{
+			bodyA:        bodyA,
+			bodyB:        bodyB,
+			localAnchorA: worldToLocal(bodyA, anchor),
+			localAnchorB: worldToLocal(bodyB, anchor),
+		},
+		referenceAngle:  bodyB.Rotation() - bodyA.Rotation(),
+		angularJacobian: angularJacobian(),
+		pointImpulse:    vector.NewVec2(0, 0),
+	}
+}
+
+func (rj RevoluteJoint) ReferenceAngle() float64 {
+	return rj.referenceAngle
+}
+
+func (rj RevoluteJoint) JointAngle() float64 {
+	return rj.bodyB.Rotation() - rj.bodyA.Rotation() - rj.referenceAngle
+}
+
+func (rj RevoluteJoint) JointSpeed() float64 {
+	return rj.bodyB.AngularVel() - rj.bodyA.AngularVel()
+}
+
+func (rj RevoluteJoint) LimitEnabled() bool {
+	return rj.limitEnabled
+}
+
+func (rj *RevoluteJoint) SetLimitEnabled(enabled bool) {
+	rj.limitEnabled = enabled
+}
+
+func (rj RevoluteJoint) LowerAngle() float64 {
+	return rj.lowerAngle
+}
+
+func (rj RevoluteJoint) UpperAngle() float64 {
+	return rj.upperAngle
+}
+
+// In radians, relative to the reference angle.
+func (rj *RevoluteJoint) SetLimits(lower, upper float64) {
+	rj.lowerAngle = math.Min(lower, upper)
+	rj.upperAngle = math.Max(lower, upper)
+}
+
+func (rj RevoluteJoint) MotorEnabled() bool {
+	return rj.motorEnabled
+}
+
+func (rj *RevoluteJoint) SetMotorEnabled(enabled bool) {
+	rj.motorEnabled = enabled
+}
+
+func (rj RevoluteJoint) MotorSpeed() float64 {
+	return rj.motorSpeed
+}
+
+func (rj *RevoluteJoint) SetMotorSpeed(speed float64) {
+	rj.motorSpeed = speed
+}
+
+func (rj RevoluteJoint) MaxMotorTorque() float64 {
+	return rj.maxMotorTorque
+}
+
+func (rj *RevoluteJoint) SetMaxMotorTorque(torque float64) {
+	rj.maxMotorTorque = torque
+}
+
+func (rj RevoluteJoint) MotorImpulse() float64 {
+	return rj.motorImpulse
+}
+
+func (rj *RevoluteJoint) PreSolve(dt float64) {
+	rj.decoratedA, rj.decoratedB = rj.decorated()
+	rj.dt = dt
+
+	relativeA, relativeB := rj.relativeAnchors()
+
+	rj.pointJacobian = pointJacobian(relativeA, relativeB)
+
+	k := effectiveMass(rj.pointJacobian, rj.decoratedA, rj.decoratedB)
+	rj.pointMass = matrix.NewMat2(k.At(0, 0), k.At(0, 1), k.At(1, 0), k.At(1, 1))
+
+	rj.angularMass = invertOrZero(effectiveMass(rj.angularJacobian, rj.decoratedA, rj.decoratedB).At(0, 0))
+
+	anchorA := rj.bodyA.Position().Add(relativeA)
+	anchorB := rj.bodyB.Position().Add(relativeB)
+	rj.pointBias = anchorB.Sub(anchorA).Scale(Config.BAUMGARTE / dt)
+
+	rj.angle = rj.JointAngle()
+
+	if !rj.motorEnabled {
+		rj.motorImpulse = 0
+	}
+
+	if !rj.limitEnabled {
+		rj.lowerImpulse, rj.upperImpulse = 0, 0
+	}
+
+	// Warm start with the impulses carried over from last step.
+	applyJacobianImpulse(rj.pointJacobian, rj.decoratedA, rj.decoratedB,
+		rj.pointImpulse.X(), rj.pointImpulse.Y(),
+	)
+	rj.applyAngularImpulse(rj.motorImpulse + rj.lowerImpulse - rj.upperImpulse)
+}
+
+func (rj *RevoluteJoint) Solve() {
+	// Motor and limits first, the point constraint matters most.
+	if rj.motorEnabled {
+		speed := rj.angularSpeed() - rj.motorSpeed
+		lambda := -rj.angularMass * speed
+
+		maxImpulse := rj.maxMotorTorque * rj.dt
+		oldImpulse := rj.motorImpulse
+		rj.motorImpulse = clamp(oldImpulse+lambda, -maxImpulse, maxImpulse)
+		rj.applyAngularImpulse(rj.motorImpulse - oldImpulse)
+	}
+
+	if rj.limitEnabled {
+		// Lower limit can only push the angle up.
+		lambda := -rj.angularMass * (rj.angularSpeed() + limitBias(rj.angle-rj.lowerAngle, rj.dt))
+
+		oldLower := rj.lowerImpulse
+		rj.lowerImpulse = math.Max(oldLower+lambda, 0)
+		rj.applyAngularImpulse(rj.lowerImpulse - oldLower)
+
+		// Upper limit can only push it down.
+		lambda = -rj.angularMass * (-rj.angularSpeed() + limitBias(rj.upperAngle-rj.angle, rj.dt))
+
+		oldUpper := rj.upperImpulse
+		rj.upperImpulse = math.Max(oldUpper+lambda, 0)
+		rj.applyAngularImpulse(-(rj.upperImpulse - oldUpper))
+	}
+
+	speeds := rj.pointJacobian.MulVec(velocityVector(rj.decoratedA, rj.decoratedB))
+	lambda := solveBlock(rj.pointMass, vector.NewVec2(speeds[0], speeds[1]).Add(rj.pointBias).Scale(-1))
+
+	rj.pointImpulse = rj.pointImpulse.Add(lambda)
+	applyJacobianImpulse(rj.pointJacobian, rj.decoratedA, rj.decoratedB, lambda.X(), lambda.Y())
+}
+
+func (rj *RevoluteJoint) PostSolve() {}
+
+func (rj RevoluteJoint) angularSpeed() float64 {
+	return rj.angularJacobian.MulVec(velocityVector(rj.decoratedA, rj.decoratedB))[0]
+}
+
+func (rj RevoluteJoint) applyAngularImpulse(lambda float64) {
+	if lambda == 0 {
+		return
+	}
+
+	applyJacobianImpulse(rj.angularJacobian, rj.decoratedA, rj.decoratedB, lambda)
+}
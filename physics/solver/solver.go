@@ -0,0 +1,152 @@
+package solver
+
+import (
+	"math"
+
+	physics_components "github.com/kainn9/tteokbokki/physics/components"
+	"github.com/kainn9/tteokbokki/physics/decorators"
+	entitysubset "github.com/kainn9/tteokbokki/physics/entity_subset"
+)
+
+type solverConfig struct {
+	// Fraction of the penetration corrected per step.
+	BAUMGARTE float64
+	// Penetration allowed before correcting, in pixels.
+	PENETRATION_SLOP float64
+	// Minimum approach speed before elasticity is applied.
+	RESTITUTION_THRESHOLD float64
+}
+
+var Config = &solverConfig{
+	BAUMGARTE:             0.2,
+	PENETRATION_SLOP:      0.5,
+	RESTITUTION_THRESHOLD: 1.0,
+}
+
+type ConstraintFace interface {
+	PreSolve(dt float64)
+	Solve()
+	PostSolve()
+}
+
+// Sequential impulses: every constraint is solved on its own in turn and
+// the whole set is repeated iterations times so they converge together.
+func Solve(constraints []ConstraintFace, iterations int, dt float64) {
+	for _, c := range constraints {
+		c.PreSolve(dt)
+	}
+
+	for i := 0; i < iterations; i++ {
+		for _, c := range constraints {
+			c.Solve()
+		}
+	}
+
+	for _, c := range constraints {
+		c.PostSolve()
+	}
+}
+
+// Exposes the underlying data, so friction/elasticity can be changed
+// before solving and the accumulated impulses read afterwards.
+type PenConstraintFace interface {
+	ConstraintFace
+	Constraint() *physics_components.PenConstraint
+}
+
+type penConstraint struct {
+	*physics_components.PenConstraint
+
+	bodyA, bodyB decorators.CollisionRigidBodyDecoratorFace
+
+	// Inverse of J * M^-1 * J^T for each row, refreshed by PreSolve.
+	normalMass, tangentMass float64
+}
+
+func NewPenConstraint(
+	collision *physics_components.Collision,
+	bodyA, bodyB entitysubset.RigidBodyFace,
+) PenConstraintFace {
+	pc := physics_components.NewPenConstraint(collision, bodyA, bodyB)
+	pc.Friction = (bodyA.Friction() + bodyB.Friction()) / 2
+	pc.Elasticity = (bodyA.Elasticity() + bodyB.Elasticity()) / 2
+
+	return &penConstraint{
+		PenConstraint: pc,
+		bodyA:         decorators.NewCollisionRigidBodyDecorator(bodyA),
+		bodyB:         decorators.NewCollisionRigidBodyDecorator(bodyB),
+	}
+}
+
+func (pc *penConstraint) PreSolve(dt float64) {
+	bodyA, bodyB := pc.bodyA, pc.bodyB
+
+	pointA := localToWorld(pc.ACollisionPointLocal, bodyA)
+	pointB := localToWorld(pc.BCollisionPointLocal, bodyB)
+
+	relativePositionA := pointA.Sub(bodyA.Position())
+	relativePositionB := pointB.Sub(bodyB.Position())
+
+	normal := pc.Normal.Rotate(bodyA.Rotation())
+	tangent := normal.Perpendicular()
+
+	pc.Jacobian.SetRow(0,
+		-normal.X(), -normal.Y(), -relativePositionA.CrossProduct(normal),
+		normal.X(), normal.Y(), relativePositionB.CrossProduct(normal),
+	)
+	pc.Jacobian.SetRow(1,
+		-tangent.X(), -tangent.Y(), -relativePositionA.CrossProduct(tangent),
+		tangent.X(), tangent.Y(), relativePositionB.CrossProduct(tangent),
+	)
+	pc.JacobianTranspose = pc.Jacobian.Transpose()
+
+	lhs := pc.Jacobian.Mul(inverseMassMatrix(bodyA, bodyB)).Mul(pc.JacobianTranspose)
+	pc.normalMass = invertOrZero(lhs.At(0, 0))
+	pc.tangentMass = invertOrZero(lhs.At(1, 1))
+
+	// Points are on opposite sides of each others surface while penetrating.
+	depth := pointA.Sub(pointB).ScalarProduct(normal)
+	pc.Bias = -(Config.BAUMGARTE / dt) * math.Max(depth-Config.PENETRATION_SLOP, 0)
+
+	approachSpeed := pc.Jacobian.MulVec(velocityVector(bodyA, bodyB))[0]
+	if approachSpeed < -Config.RESTITUTION_THRESHOLD {
+		pc.Bias += pc.Elasticity * approachSpeed
+	}
+
+	// Warm start with the impulses carried over from last step.
+	pc.applyImpulses(pc.CachedLambda[0], pc.CachedLambda[1])
+}
+
+func (pc *penConstraint) Solve() {
+	// Friction first, clamped by the current normal impulse.
+	tangentSpeed := pc.Jacobian.MulVec(velocityVector(pc.bodyA, pc.bodyB))[1]
+	lambdaTangent := -pc.tangentMass * tangentSpeed
+
+	maxFriction := pc.Friction * pc.CachedLambda[0]
+	oldTangent := pc.CachedLambda[1]
+	pc.CachedLambda[1] = clamp(oldTangent+lambdaTangent, -maxFriction, maxFriction)
+	lambdaTangent = pc.CachedLambda[1] - oldTangent
+
+	pc.applyImpulses(0, lambdaTangent)
+
+	// The normal impulse can only ever push the bodies apart.
+	normalSpeed := pc.Jacobian.MulVec(velocityVector(pc.bodyA, pc.bodyB))[0]
+	lambdaNormal := -pc.normalMass * (normalSpeed + pc.Bias)
+
+	oldNormal := pc.CachedLambda[0]
+	pc.CachedLambda[0] = math.Max(oldNormal+lambdaNormal, 0)
+	lambdaNormal = pc.CachedLambda[0] - oldNormal
+
+	pc.applyImpulses(lambdaNormal, 0)
+}
+
+func (pc *penConstraint) PostSolve() {}
+
+func (pc *penConstraint) Constraint() *physics_components.PenConstraint {
+	return pc.PenConstraint
+}
+
+func (pc penConstraint) applyImpulses(lambdaNormal, lambdaTangent float64) {
+	impulses := pc.JacobianTranspose.MulVec([]float64{lambdaNormal, lambdaTangent})
+	applyImpulseVector(pc.bodyA, pc.bodyB, impulses)
+}
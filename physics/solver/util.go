@@ -0,0 +1,64 @@
+package solver
+
+import (
+	"github.com/kainn9/tteokbokki/physics/decorators"
+	"github.com/kainn9/tteokbokki/physics/matrix"
+	"github.com/kainn9/tteokbokki/physics/physics"
+	transform_components "github.com/kainn9/tteokbokki/transform/components"
+	"github.com/kainn9/tteokbokki/vector"
+)
+
+func localToWorld(point vector.Vec2Face, trans transform_components.TransformFace) vector.Vec2Face {
+	return point.Rotate(trans.Rotation()).Add(trans.Position())
+}
+
+// Velocities of both bodies as a single column, matching
+// the columns of a two body Jacobian.
+func velocityVector(bodyA, bodyB decorators.CollisionRigidBodyDecoratorFace) []float64 {
+	return []float64{
+		bodyA.Vel().X(), bodyA.Vel().Y(), bodyA.AngularVel(),
+		bodyB.Vel().X(), bodyB.Vel().Y(), bodyB.AngularVel(),
+	}
+}
+
+func inverseMassMatrix(bodyA, bodyB decorators.CollisionRigidBodyDecoratorFace) matrix.MatN {
+	m := matrix.NewMatN(6, 6)
+
+	m.Set(0, 0, bodyA.InverseMass())
+	m.Set(1, 1, bodyA.InverseMass())
+	m.Set(2, 2, bodyA.InverseAngularMass())
+	m.Set(3, 3, bodyB.InverseMass())
+	m.Set(4, 4, bodyB.InverseMass())
+	m.Set(5, 5, bodyB.InverseAngularMass())
+
+	return m
+}
+
+// Applies a column of impulses laid out like velocityVector.
+func applyImpulseVector(bodyA, bodyB decorators.CollisionRigidBodyDecoratorFace, impulses []float64) {
+	physics.ApplyLinearImpulse(bodyA, vector.NewVec2(impulses[0], impulses[1]))
+	physics.ApplyAngularImpulse(bodyA, impulses[2])
+
+	physics.ApplyLinearImpulse(bodyB, vector.NewVec2(impulses[3], impulses[4]))
+	physics.ApplyAngularImpulse(bodyB, impulses[5])
+}
+
+func invertOrZero(v float64) float64 {
+	if v == 0 {
+		return 0
+	}
+
+	return 1 / v
+}
+
+func clamp(v, min, max float64) float64 {
+	if v > max {
+		return max
+	}
+
+	if v < min {
+		return min
+	}
+
+	return v
+}
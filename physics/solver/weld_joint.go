@@ -0,0 +1,200 @@
+package solver
+
+import (
+	"math"
+
+	"github.com/kainn9/tteokbokki/physics/decorators"
+	entitysubset "github.com/kainn9/tteokbokki/physics/entity_subset"
+	"github.com/kainn9/tteokbokki/physics/matrix"
+	"github.com/kainn9/tteokbokki/vector"
+)
+
+// Glues the bodies together at an anchor, locking their relative
+// position and angle. Breaks once the force or torque needed to hold
+// them goes past its threshold, thresholds are off by default.
+type WeldJointFace interface {
+	BreakableJointFace
+
+	LocalAnchorA() vector.Vec2Face
+	LocalAnchorB() vector.Vec2Face
+
+	// Relative angle of B to A when the joint was made.
+	ReferenceAngle() float64
+
+	// Force and torque applied to B during the last step.
+	ReactionForce() vector.Vec2Face
+	ReactionTorque() float64
+
+	// Zero never breaks.
+	BreakForce() float64
+	SetBreakForce(float64)
+	BreakTorque() float64
+	SetBreakTorque(float64)
+}
+
+type WeldJoint struct {
+	joint
+
+	referenceAngle float64
+
+	breakForce, breakTorque float64
+	broken                  bool
+	onBreak                 BreakFunc
+
+	// Refreshed by PreSolve. Rows of the Jacobian are the anchors
+	// along x and y, then the relative angle.
+	decoratedA, decoratedB decorators.CollisionRigidBodyDecoratorFace
+	jacobian               matrix.MatN
+	mass                   matrix.Mat3
+	bias                   [3]float64
+	dt                     float64
+
+	// Accumulated impulse, carried over for warm starting.
+	impulse [3]float64
+
+	reactionForce  vector.Vec2Face
+	reactionTorque float64
+}
+
+// Anchor is in world space, both bodies keep it where it is now.
+func NewWeldJoint(bodyA, bodyB entitysubset.RigidBodyFace, anchor vector.Vec2Face) WeldJointFace {
+	return &WeldJoint{
+		joint: joint{
+			bodyA:        bodyA,
+			bodyB:        bodyB,
+			localAnchorA: worldToLocal(bodyA, anchor),
+			localAnchorB: worldToLocal(bodyB, anchor),
+		},
+		referenceAngle: bodyB.Rotation() - bodyA.Rotation(),
+		reactionForce:  vector.NewVec2(0, 0),
+	}
+}
+
+func (wj WeldJoint) ReferenceAngle() float64 {
+	return wj.referenceAngle
+}
+
+func (wj WeldJoint) ReactionForce() vector.Vec2Face {
+	return wj.reactionForce
+}
+
+func (wj WeldJoint) ReactionTorque() float64 {
+	return wj.reactionTorque
+}
+
+func (wj WeldJoint) BreakForce() float64 {
+	return wj.breakForce
+}
+
+func (wj *WeldJoint) SetBreakForce(force float64) {
+	wj.breakForce = force
+}
+
+func (wj WeldJoint) BreakTorque() float64 {
+	return wj.breakTorque
+}
+
+func (wj *WeldJoint) SetBreakTorque(torque float64) {
+	wj.breakTorque = torque
+}
+
+func (wj WeldJoint) Broken() bool {
+	return wj.broken
+}
+
+func (wj WeldJoint) OnBreak() BreakFunc {
+	return wj.onBreak
+}
+
+func (wj *WeldJoint) SetOnBreak(fn BreakFunc) {
+	wj.onBreak = fn
+}
+
+func (wj *WeldJoint) PreSolve(dt float64) {
+	wj.decoratedA, wj.decoratedB = wj.decorated()
+	wj.dt = dt
+
+	if wj.broken {
+		return
+	}
+
+	relativeA, relativeB := wj.relativeAnchors()
+
+	point := pointJacobian(relativeA, relativeB)
+
+	wj.jacobian = matrix.NewMatN(3, 6)
+	wj.jacobian.SetRow(0, point.Row(0)...)
+	wj.jacobian.SetRow(1, point.Row(1)...)
+	wj.jacobian.SetRow(2, angularJacobian().Row(0)...)
+
+	k := effectiveMass(wj.jacobian, wj.decoratedA, wj.decoratedB)
+	wj.mass = matrix.NewMat3(
+		k.At(0, 0), k.At(0, 1), k.At(0, 2),
+		k.At(1, 0), k.At(1, 1), k.At(1, 2),
+		k.At(2, 0), k.At(2, 1), k.At(2, 2),
+	)
+
+	delta := wj.anchorDelta()
+	angle := wj.bodyB.Rotation() - wj.bodyA.Rotation() - wj.referenceAngle
+	biasFactor := Config.BAUMGARTE / dt
+	wj.bias = [3]float64{delta.X() * biasFactor, delta.Y() * biasFactor, angle * biasFactor}
+
+	// Warm start with the impulse carried over from last step.
+	applyJacobianImpulse(wj.jacobian, wj.decoratedA, wj.decoratedB, wj.impulse[:]...)
+}
+
+func (wj *WeldJoint) Solve() {
+	if wj.broken {
+		return
+	}
+
+	speeds := wj.jacobian.MulVec(velocityVector(wj.decoratedA, wj.decoratedB))
+
+	rhs := [3]float64{}
+	for i := range rhs {
+		rhs[i] = -(speeds[i] + wj.bias[i])
+	}
+
+	lambda := wj.solveMass(rhs)
+
+	for i := range wj.impulse {
+		wj.impulse[i] += lambda[i]
+	}
+
+	applyJacobianImpulse(wj.jacobian, wj.decoratedA, wj.decoratedB, lambda[:]...)
+}
+
+// Turns the accumulated impulse into a force and breaks the joint when
+// it is past either threshold. The impulse already applied this step
+// stays, the joint only lets go from the next step on.
+func (wj *WeldJoint) PostSolve() {
+	if wj.broken {
+		return
+	}
+
+	wj.reactionForce = vector.NewVec2(wj.impulse[0], wj.impulse[1]).Scale(1 / wj.dt)
+	wj.reactionTorque = wj.impulse[2] / wj.dt
+
+	if wj.breakForce > 0 && wj.reactionForce.Mag() > wj.breakForce {
+		wj.broken = true
+	}
+
+	if wj.breakTorque > 0 && math.Abs(wj.reactionTorque) > wj.breakTorque {
+		wj.broken = true
+	}
+}
+
+// Bodies without angular mass leave the matrix singular, the point
+// and angle are solved on their own then.
+func (wj WeldJoint) solveMass(rhs [3]float64) [3]float64 {
+	if wj.mass.Determinant() != 0 {
+		return wj.mass.Solve(rhs)
+	}
+
+	point := solveBlock(
+		matrix.NewMat2(wj.mass[0][0], wj.mass[0][1], wj.mass[1][0], wj.mass[1][1]),
+		vector.NewVec2(rhs[0], rhs[1]),
+	)
+
+	return [3]float64{point.X(), point.Y(), rhs[2] * invertOrZero(wj.mass[2][2])}
+}
@@ -0,0 +1,165 @@
+package stepper
+
+import (
+	"math"
+
+	transform_components "github.com/kainn9/tteokbokki/transform/components"
+)
+
+// Runs a simulation step function at a fixed rate regardless of
+// how long each rendered frame takes, and keeps snapshots of tracked
+// transforms so renderers can interpolate between the last two steps.
+type FixedStepperFace interface {
+	Advance(frameDt float64) (steps int)
+
+	StepSize() float64
+	MaxSteps() int
+	Alpha() float64
+
+	Track(trans transform_components.TransformFace)
+	Untrack(trans transform_components.TransformFace)
+
+	Previous(trans transform_components.TransformFace) transform_components.TransformFace
+	Current(trans transform_components.TransformFace) transform_components.TransformFace
+	Interpolated(trans transform_components.TransformFace) transform_components.TransformFace
+}
+
+type FixedStepper struct {
+	stepSize    float64
+	maxSteps    int
+	accumulator float64
+	alpha       float64
+
+	step func(dt float64)
+
+	snapshots map[transform_components.TransformFace]*snapshot
+}
+
+type snapshot struct {
+	previous, current transform_components.TransformFace
+}
+
+// Step is called with stepSize as dt, usually physics.Integrate
+// wrapped in a closure or a world's Step method. Panics when
+// stepSize is not positive.
+func NewFixedStepper(stepSize float64, maxSteps int, step func(dt float64)) FixedStepperFace {
+	if !(stepSize > 0) {
+		panic("stepper: step size must be positive")
+	}
+
+	if maxSteps < 1 {
+		maxSteps = 1
+	}
+
+	return &FixedStepper{
+		stepSize:  stepSize,
+		maxSteps:  maxSteps,
+		step:      step,
+		snapshots: make(map[transform_components.TransformFace]*snapshot),
+	}
+}
+
+// Consumes a variable wall-clock delta and runs as many fixed
+// steps as fit, never more than MaxSteps per call.
+func (fs *FixedStepper) Advance(frameDt float64) (steps int) {
+	// Clamp long frames to avoid the spiral of death, where each
+	// frame has to run more steps than the last to catch up.
+	maxFrameDt := fs.stepSize * float64(fs.maxSteps)
+	fs.accumulator += math.Max(0, math.Min(frameDt, maxFrameDt))
+
+	for fs.accumulator >= fs.stepSize && steps < fs.maxSteps {
+		for trans, snap := range fs.snapshots {
+			copyTransform(snap.previous, trans)
+		}
+
+		fs.step(fs.stepSize)
+
+		fs.accumulator -= fs.stepSize
+		steps++
+	}
+
+	// Anything left over after the clamp is dropped.
+	if fs.accumulator >= fs.stepSize {
+		fs.accumulator = math.Mod(fs.accumulator, fs.stepSize)
+	}
+
+	if steps > 0 {
+		for trans, snap := range fs.snapshots {
+			copyTransform(snap.current, trans)
+		}
+	}
+
+	fs.alpha = fs.accumulator / fs.stepSize
+
+	return steps
+}
+
+func (fs FixedStepper) StepSize() float64 {
+	return fs.stepSize
+}
+
+func (fs FixedStepper) MaxSteps() int {
+	return fs.maxSteps
+}
+
+// How far between the previous and current step the
+// leftover time is, from 0 to 1.
+func (fs FixedStepper) Alpha() float64 {
+	return fs.alpha
+}
+
+func (fs *FixedStepper) Track(trans transform_components.TransformFace) {
+	fs.snapshots[trans] = &snapshot{
+		previous: cloneTransform(trans),
+		current:  cloneTransform(trans),
+	}
+}
+
+func (fs *FixedStepper) Untrack(trans transform_components.TransformFace) {
+	delete(fs.snapshots, trans)
+}
+
+// Returns nil for transforms that are not tracked.
+func (fs FixedStepper) Previous(trans transform_components.TransformFace) transform_components.TransformFace {
+	snap, ok := fs.snapshots[trans]
+	if !ok {
+		return nil
+	}
+
+	return snap.previous
+}
+
+// Returns nil for transforms that are not tracked.
+func (fs FixedStepper) Current(trans transform_components.TransformFace) transform_components.TransformFace {
+	snap, ok := fs.snapshots[trans]
+	if !ok {
+		return nil
+	}
+
+	return snap.current
+}
+
+// Returns a new transform blended between the previous and
+// current snapshots by Alpha, or nil for untracked transforms.
+func (fs FixedStepper) Interpolated(trans transform_components.TransformFace) transform_components.TransformFace {
+	snap, ok := fs.snapshots[trans]
+	if !ok {
+		return nil
+	}
+
+	prev, curr := snap.previous, snap.current
+	alpha := fs.alpha
+
+	interpolated := transform_components.NewTransform(
+		lerp(prev.Position().X(), curr.Position().X(), alpha),
+		lerp(prev.Position().Y(), curr.Position().Y(), alpha),
+		lerp(prev.Rotation(), curr.Rotation(), alpha),
+	)
+
+	interpolated.SetScale(
+		lerp(prev.Scale().X(), curr.Scale().X(), alpha),
+		lerp(prev.Scale().Y(), curr.Scale().Y(), alpha),
+	)
+
+	return interpolated
+}
@@ -0,0 +1,97 @@
+package stepper
+
+import (
+	"math"
+	"testing"
+
+	transform_components "github.com/kainn9/tteokbokki/transform/components"
+	"github.com/kainn9/tteokbokki/vector"
+)
+
+// Step sizes and deltas are all exact in binary, so the
+// accumulator never picks up rounding error.
+func TestAdvanceRunsFixedSteps(t *testing.T) {
+	calls := 0
+	fs := NewFixedStepper(0.25, 4, func(dt float64) {
+		calls++
+
+		if dt != 0.25 {
+			t.Errorf("expected every step to be 0.25, got %v", dt)
+		}
+	})
+
+	frames := []struct {
+		frameDt float64
+		steps   int
+		alpha   float64
+	}{
+		{0.125, 0, 0.5},
+		{0.125, 1, 0},
+		{0.375, 1, 0.5},
+		{0.625, 3, 0},
+		{-1, 0, 0},
+	}
+
+	for i, frame := range frames {
+		if steps := fs.Advance(frame.frameDt); steps != frame.steps {
+			t.Errorf("frame %d: expected %d steps, got %d", i, frame.steps, steps)
+		}
+
+		if fs.Alpha() != frame.alpha {
+			t.Errorf("frame %d: expected an alpha of %v, got %v", i, frame.alpha, fs.Alpha())
+		}
+	}
+
+	if calls != 5 {
+		t.Errorf("expected step to be called 5 times, got %d", calls)
+	}
+}
+
+func TestAdvanceClampsLongFrames(t *testing.T) {
+	fs := NewFixedStepper(0.25, 4, func(dt float64) {})
+
+	// A ten second hitch only runs MaxSteps and does not carry over.
+	if steps := fs.Advance(10); steps != 4 {
+		t.Errorf("expected the hitch to be clamped to 4 steps, got %d", steps)
+	}
+
+	if steps := fs.Advance(0.125); steps != 0 {
+		t.Errorf("expected nothing left over from the hitch, got %d steps", steps)
+	}
+}
+
+func TestInterpolatedBlendsSnapshots(t *testing.T) {
+	trans := transform_components.NewTransform(0, 0, 0)
+
+	fs := NewFixedStepper(0.25, 4, func(dt float64) {
+		trans.SetPosition(trans.Position().Add(vector.NewVec2(10, 0)))
+		trans.SetRotation(trans.Rotation() + 1)
+	})
+	fs.Track(trans)
+
+	fs.Advance(0.375)
+
+	if x := fs.Previous(trans).Position().X(); x != 0 {
+		t.Errorf("expected the previous snapshot at x 0, got %v", x)
+	}
+
+	if x := fs.Current(trans).Position().X(); x != 10 {
+		t.Errorf("expected the current snapshot at x 10, got %v", x)
+	}
+
+	interpolated := fs.Interpolated(trans)
+
+	if x := interpolated.Position().X(); math.Abs(x-5) > 1e-9 {
+		t.Errorf("expected the interpolated x halfway at 5, got %v", x)
+	}
+
+	if rotation := interpolated.Rotation(); math.Abs(rotation-0.5) > 1e-9 {
+		t.Errorf("expected the interpolated rotation halfway at 0.5, got %v", rotation)
+	}
+
+	fs.Untrack(trans)
+
+	if fs.Interpolated(trans) != nil {
+		t.Error("expected nil for an untracked transform")
+	}
+}
@@ -0,0 +1,21 @@
+package stepper
+
+import transform_components "github.com/kainn9/tteokbokki/transform/components"
+
+func cloneTransform(trans transform_components.TransformFace) transform_components.TransformFace {
+	clone := transform_components.NewTransform(0, 0, 0)
+	copyTransform(clone, trans)
+
+	return clone
+}
+
+// Copies by value so later changes to src are not seen by dst.
+func copyTransform(dst, src transform_components.TransformFace) {
+	dst.SetPosition(src.Position().Clone())
+	dst.SetRotation(src.Rotation())
+	dst.SetScale(src.Scale().X(), src.Scale().Y())
+}
+
+func lerp(a, b, alpha float64) float64 {
+	return a + (b-a)*alpha
+}
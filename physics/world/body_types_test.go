@@ -0,0 +1,65 @@
+package world
+
+import (
+	"math"
+	"testing"
+
+	physics_components "github.com/kainn9/tteokbokki/physics/components"
+	"github.com/kainn9/tteokbokki/vector"
+)
+
+func TestKinematicBodyPushesWithoutReacting(t *testing.T) {
+	forEachSolver(t, func(t *testing.T, w WorldFace) {
+		addFloor(w)
+
+		pusher := newBox(100, 270, 40, 40, 0)
+		pusher.SetBodyType(physics_components.KinematicBody)
+		pusher.SetVel(vector.NewVec2(100, 0))
+		w.Add(pusher)
+
+		crate := newBox(200, 270, 40, 40, 1)
+		w.Add(crate)
+
+		stepWorld(w, 120, func(step int) {
+			// Follows its velocity exactly, ignoring gravity and the crate.
+			expected := 100 + 100*testDt*float64(step+1)
+			if x := pusher.Position().X(); math.Abs(x-expected) > 1e-6 || pusher.Position().Y() != 270 {
+				t.Fatalf("step %d: expected the pusher at (%.2f, 270), it is at %v", step, expected, pusher.Position())
+			}
+
+			if vel := pusher.Vel(); vel.X() != 100 || vel.Y() != 0 {
+				t.Fatalf("step %d: expected the pushers velocity to stay (100, 0), got %v", step, vel)
+			}
+
+			if gap := crate.Position().X() - pusher.Position().X(); gap < 38 {
+				t.Fatalf("step %d: expected the crate to be pushed ahead, they are only %.2f apart", step, gap)
+			}
+		})
+	})
+}
+
+func TestKinematicPlatformCarriesRider(t *testing.T) {
+	forEachSolver(t, func(t *testing.T, w WorldFace) {
+		platform := newBox(200, 300, 200, 20, 0)
+		platform.SetBodyType(physics_components.KinematicBody)
+		platform.SetVel(vector.NewVec2(60, 0))
+		w.Add(platform)
+
+		rider := newBox(200, 280, 20, 20, 1)
+		w.Add(rider)
+
+		stepWorld(w, 120, func(step int) {
+			if y := rider.Position().Y(); math.Abs(y-280) > 2 {
+				t.Fatalf("step %d: expected the rider to stay on the platform at y 280, it is at %.2f", step, y)
+			}
+		})
+
+		// Friction gets it up to the platforms speed after a few steps.
+		carried := rider.Position().X() - 200
+		moved := platform.Position().X() - 200
+
+		if carried < moved*0.9 {
+			t.Errorf("expected the rider to be carried along about %.0f, it only moved %.2f", moved, carried)
+		}
+	})
+}
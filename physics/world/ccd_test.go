@@ -0,0 +1,64 @@
+package world
+
+import (
+	"testing"
+
+	entitysubset "github.com/kainn9/tteokbokki/physics/entity_subset"
+	"github.com/kainn9/tteokbokki/vector"
+)
+
+// Top at y = 295, far thinner than a steps worth of bullet motion.
+func addThinFloor(w WorldFace) {
+	w.Add(newBox(200, 300, 400, 10, 0))
+}
+
+func TestBulletsStopAtThinFloor(t *testing.T) {
+	shapes := []struct {
+		name string
+		new  func() entitysubset.RigidBodyFace
+	}{
+		{"box", func() entitysubset.RigidBodyFace { return newBox(200, 200, 6, 6, 1) }},
+		{"circle", func() entitysubset.RigidBodyFace { return newCircle(200, 200, 3, 1) }},
+	}
+
+	for _, shape := range shapes {
+		t.Run(shape.name, func(t *testing.T) {
+			forEachSolver(t, func(t *testing.T, w WorldFace) {
+				addThinFloor(w)
+
+				bullet := shape.new()
+				bullet.SetBullet(true)
+				bullet.SetVel(vector.NewVec2(0, 9000))
+				w.Add(bullet)
+
+				stepWorld(w, 10, func(step int) {
+					// Center of a 6 pixel body resting on the floor is at 292.
+					if y := bullet.Position().Y(); y > 293 {
+						t.Fatalf("step %d: bullet went into the floor, at y %.2f with vel %v", step, y, bullet.Vel())
+					}
+				})
+			})
+		})
+	}
+}
+
+// Touching the floor at the start of a step must not stop a bullet
+// that is only moving along it.
+func TestBulletSlidesAlongFloor(t *testing.T) {
+	forEachSolver(t, func(t *testing.T, w WorldFace) {
+		addThinFloor(w)
+
+		bullet := newBox(100, 292, 6, 6, 1)
+		bullet.SetBullet(true)
+		bullet.SetVel(vector.NewVec2(600, 0))
+		w.Add(bullet)
+
+		previousX := bullet.Position().X()
+		stepWorld(w, 10, func(step int) {
+			if x := bullet.Position().X(); x <= previousX {
+				t.Fatalf("step %d: expected the bullet to keep sliding, stuck at x %.2f", step, x)
+			}
+			previousX = bullet.Position().X()
+		})
+	})
+}
@@ -0,0 +1,114 @@
+package world
+
+import (
+	physics_components "github.com/kainn9/tteokbokki/physics/components"
+	entitysubset "github.com/kainn9/tteokbokki/physics/entity_subset"
+	"github.com/kainn9/tteokbokki/physics/resolver"
+)
+
+// A pair of touching bodies, with A always added to the world first.
+type Contact struct {
+	BodyA, BodyB entitysubset.RigidBodyFace
+	// Nil when passed to EndContact.
+	Manifold *physics_components.Manifold
+
+	// PreSolve may change these, they only last for the current step.
+	Enabled              bool
+	Friction, Elasticity float64
+}
+
+// Impulses applied at each contact point of the manifold, in order.
+// Normal impulses are positive when pushing the bodies apart.
+type ContactImpulse struct {
+	NormalImpulses, TangentImpulses []float64
+}
+
+// Receives contacts between non sensor bodies. Events are sent during
+// Step, so Add/Remove calls are queued.
+type ContactListenerFace interface {
+	// Sent the first step a pair touches, before PreSolve.
+	BeginContact(contact *Contact)
+	// Sent the first step a pair stops touching, or the step after
+	// either body is removed from the world.
+	EndContact(contact *Contact)
+	// Sent every step the pair touches, before anything is resolved.
+	PreSolve(contact *Contact)
+	// Only sent for contacts that were still enabled after PreSolve.
+	PostSolve(contact *Contact, impulse ContactImpulse)
+}
+
+func (w World) ContactListener() ContactListenerFace {
+	return w.contactListener
+}
+
+func (w *World) SetContactListener(listener ContactListenerFace) {
+	w.contactListener = listener
+}
+
+// Tracks the touching pair and runs BeginContact/PreSolve on it.
+func (w World) touchContact(
+	bodyA, bodyB entitysubset.RigidBodyFace,
+	manifold *physics_components.Manifold,
+) *Contact {
+	friction, elasticity := resolver.Util.MixedMaterial(bodyA, bodyB)
+
+	contact := &Contact{
+		BodyA:      bodyA,
+		BodyB:      bodyB,
+		Manifold:   manifold,
+		Enabled:    true,
+		Friction:   friction,
+		Elasticity: elasticity,
+	}
+
+	isNew := w.contacts.add(bodyPair{bodyA, bodyB})
+	w.applyOneWay(contact)
+
+	if w.contactListener == nil {
+		return contact
+	}
+
+	if isNew {
+		w.contactListener.BeginContact(contact)
+	}
+
+	w.contactListener.PreSolve(contact)
+
+	return contact
+}
+
+func (w World) postSolveContact(contact *Contact, impulse ContactImpulse) {
+	if w.contactListener != nil {
+		w.contactListener.PostSolve(contact, impulse)
+	}
+}
+
+// Reads the accumulated impulses back out of the solved constraints.
+func (w World) postSolveConstraints(edge islandEdge) {
+	if w.contactListener == nil || edge.contact == nil {
+		return
+	}
+
+	impulse := ContactImpulse{
+		NormalImpulses:  make([]float64, len(edge.pens)),
+		TangentImpulses: make([]float64, len(edge.pens)),
+	}
+
+	for i, pc := range edge.pens {
+		impulse.NormalImpulses[i] = pc.Constraint().CachedLambda[0]
+		impulse.TangentImpulses[i] = pc.Constraint().CachedLambda[1]
+	}
+
+	w.contactListener.PostSolve(edge.contact, impulse)
+}
+
+// Ends every contact from the previous step that was not found again.
+func (w World) endContacts() {
+	w.contacts.endStep(func(pair bodyPair) {
+		delete(w.oneWayPassing, pair)
+
+		if w.contactListener != nil {
+			w.contactListener.EndContact(&Contact{BodyA: pair.bodyA, BodyB: pair.bodyB})
+		}
+	})
+}
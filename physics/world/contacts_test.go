@@ -0,0 +1,33 @@
+package world
+
+import (
+	"math"
+	"testing"
+
+	entitysubset "github.com/kainn9/tteokbokki/physics/entity_subset"
+	"github.com/kainn9/tteokbokki/physics/factory"
+)
+
+// Without warm starting each step has to find the impulses holding the
+// stack up from scratch, and the few iterations it gets leave it
+// sagging and tipping over after a couple of seconds.
+func TestWarmStartingKeepsTallStackUpright(t *testing.T) {
+	w := NewWorld(factory.Forces.DEFAULT_GRAVITY)
+	w.SetSolver(SequentialImpulseSolver)
+	w.SetSleepingEnabled(false)
+	addFloor(w)
+
+	boxes := make([]entitysubset.RigidBodyFace, 8)
+	for i := range boxes {
+		boxes[i] = newBox(200, 270-float64(i)*40, 40, 40, 1)
+		w.Add(boxes[i])
+	}
+
+	top := boxes[len(boxes)-1]
+
+	stepWorld(w, 600, func(step int) {
+		if drift := math.Abs(top.Position().X() - 200); drift > 15 {
+			t.Fatalf("step %d: expected the top of the stack to stay within 15 of x 200, it is at %v", step, top.Position())
+		}
+	})
+}
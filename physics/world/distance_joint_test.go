@@ -0,0 +1,69 @@
+package world
+
+import (
+	"math"
+	"testing"
+
+	"github.com/kainn9/tteokbokki/physics/solver"
+	"github.com/kainn9/tteokbokki/vector"
+)
+
+func TestDistanceJointPendulumKeepsLength(t *testing.T) {
+	forEachSolver(t, func(t *testing.T, w WorldFace) {
+		pivot := newBox(100, 100, 10, 10, 0)
+		bob := newBox(200, 100, 10, 10, 1)
+		w.Add(pivot)
+		w.Add(bob)
+
+		joint := solver.NewDistanceJoint(pivot, bob, vector.NewVec2(0, 0), vector.NewVec2(0, 0))
+		w.AddJoint(joint)
+
+		lowest := bob.Position().Y()
+		stepWorld(w, 600, func(step int) {
+			if lengthError := math.Abs(joint.CurrentLength() - joint.Length()); lengthError > 1 {
+				t.Fatalf("step %d: expected the length to stay within 1 of %.0f, was off by %.2f", step, joint.Length(), lengthError)
+			}
+
+			lowest = math.Max(lowest, bob.Position().Y())
+		})
+
+		// Swung down through the bottom, not stuck where it started.
+		if lowest < 199 {
+			t.Errorf("expected the bob to swing down to y 200, it only got to %.2f", lowest)
+		}
+	})
+}
+
+func TestDistanceJointRopeStaysInRange(t *testing.T) {
+	forEachSolver(t, func(t *testing.T, w WorldFace) {
+		pivot := newBox(100, 100, 10, 10, 0)
+		bob := newBox(100, 130, 10, 10, 1)
+		w.Add(pivot)
+		w.Add(bob)
+
+		rope := solver.NewDistanceJoint(pivot, bob, vector.NewVec2(0, 0), vector.NewVec2(0, 0))
+		rope.SetLengthRange(20, 60)
+		w.AddJoint(rope)
+
+		// Thrown up and sideways, so it hits both ends of the range.
+		bob.SetVel(vector.NewVec2(150, -300))
+
+		shortest := rope.CurrentLength()
+		stepWorld(w, 600, func(step int) {
+			length := rope.CurrentLength()
+			if length < 19 || length > 61 {
+				t.Fatalf("step %d: expected the length to stay in [20, 60], got %.2f", step, length)
+			}
+
+			shortest = math.Min(shortest, length)
+		})
+
+		if shortest > 21 {
+			t.Errorf("expected the throw to reach the min length, shortest was %.2f", shortest)
+		}
+
+		if length := rope.CurrentLength(); math.Abs(length-60) > 1 {
+			t.Errorf("expected the rope to end up hanging taut at 60, got %.2f", length)
+		}
+	})
+}
@@ -0,0 +1,121 @@
+package world
+
+import (
+	"testing"
+
+	"github.com/kainn9/tteokbokki/physics/broadphase"
+	physics_components "github.com/kainn9/tteokbokki/physics/components"
+	entitysubset "github.com/kainn9/tteokbokki/physics/entity_subset"
+)
+
+// Drops a box onto the floor, with filter set on the box, and reports
+// whether it fell through.
+func dropThroughFloor(t *testing.T, w WorldFace, filter physics_components.Filter) bool {
+	t.Helper()
+
+	addFloor(w)
+
+	box := newBox(200, 250, 20, 20, 1)
+	box.SetFilter(filter)
+	w.Add(box)
+
+	stepWorld(w, 60, nil)
+
+	return box.Position().Y() > 300
+}
+
+func TestFilterMaskBits(t *testing.T) {
+	forEachSolver(t, func(t *testing.T, w WorldFace) {
+		filter := physics_components.NewFilter()
+		filter.CategoryBits = 0x0002
+		filter.MaskBits = 0xFFFF &^ 0x0001
+
+		if !dropThroughFloor(t, w, filter) {
+			t.Error("expected a box masking out the floors category to fall through it")
+		}
+	})
+
+	forEachSolver(t, func(t *testing.T, w WorldFace) {
+		filter := physics_components.NewFilter()
+		filter.CategoryBits = 0x0002
+
+		if dropThroughFloor(t, w, filter) {
+			t.Error("expected a box in another category to still land on the floor")
+		}
+	})
+}
+
+func TestFilterGroupIndex(t *testing.T) {
+	forEachSolver(t, func(t *testing.T, w WorldFace) {
+		addFloor(w)
+
+		// Same negative group, so never colliding even though the
+		// masks allow it.
+		filter := physics_components.NewFilter()
+		filter.GroupIndex = -1
+
+		lower := newBox(200, 270, 40, 40, 1)
+		upper := newBox(200, 200, 20, 20, 1)
+		lower.SetFilter(filter)
+		upper.SetFilter(filter)
+		w.Add(lower)
+		w.Add(upper)
+
+		stepWorld(w, 60, nil)
+
+		if lower.Position().Y() < 265 {
+			t.Errorf("expected the lower box to rest on the floor, it is at %v", lower.Position())
+		}
+
+		if upper.Position().Y() < 275 {
+			t.Errorf("expected the upper box to fall into the lower one and land on the floor, it is at %v", upper.Position())
+		}
+	})
+}
+
+func TestShouldCollideCallback(t *testing.T) {
+	forEachSolver(t, func(t *testing.T, w WorldFace) {
+		addFloor(w)
+
+		ghost := newBox(200, 250, 20, 20, 1)
+		w.Add(ghost)
+
+		masked := newBox(300, 250, 20, 20, 1)
+		filter := physics_components.NewFilter()
+		filter.MaskBits = 0
+		masked.SetFilter(filter)
+		w.Add(masked)
+
+		w.SetShouldCollide(func(bodyA, bodyB entitysubset.RigidBodyFace) bool {
+			if bodyA == masked || bodyB == masked {
+				t.Fatal("expected pairs the filters reject to never reach the callback")
+			}
+
+			return bodyA != ghost && bodyB != ghost
+		})
+
+		stepWorld(w, 60, nil)
+
+		if ghost.Position().Y() < 300 {
+			t.Errorf("expected the callback to let the box fall through the floor, it is at %v", ghost.Position())
+		}
+	})
+}
+
+func TestQueriesSkipFilteredBodies(t *testing.T) {
+	w, circle, diamond, box := newQueryWorld()
+
+	hidden := physics_components.NewFilter()
+	hidden.CategoryBits = 0x0002
+	diamond.SetFilter(hidden)
+
+	query := physics_components.NewFilter()
+	query.MaskBits = 0x0001
+
+	everything := broadphase.NewAABB(0, 0, 400, 200)
+
+	expectBodies(t, "FilterQuery", w.QueryAABB(everything, FilterQuery(query)), circle, box)
+	expectBodies(t, "callback", w.QueryAABB(everything, func(body entitysubset.RigidBodyFace) bool {
+		return body != box
+	}), circle, diamond)
+}
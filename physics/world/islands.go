@@ -0,0 +1,98 @@
+package world
+
+import (
+	physics_components "github.com/kainn9/tteokbokki/physics/components"
+	entitysubset "github.com/kainn9/tteokbokki/physics/entity_subset"
+	"github.com/kainn9/tteokbokki/physics/solver"
+)
+
+// Group of awake dynamic bodies connected through contacts and joints,
+// which can be solved and put to sleep independently of every other island.
+// Static, kinematic and sleeping bodies never join islands, so they do
+// not link the bodies touching them together.
+type Island struct {
+	// In the order they were added to the world.
+	Bodies   []entitysubset.RigidBodyFace
+	Contacts []*Contact
+	Joints   []solver.JointFace
+
+	constraints []solver.ConstraintFace
+}
+
+// A contact or joint linking two bodies, waiting to be solved.
+type islandEdge struct {
+	bodyA, bodyB entitysubset.RigidBodyFace
+
+	contact *Contact
+	pens    []solver.PenConstraintFace
+	joint   solver.JointFace
+}
+
+// Islands found during the last step.
+func (w World) Islands() []*Island {
+	return w.islands
+}
+
+// Every awake dynamic body ends up in exactly one island, edges whose
+// bodies are both left out are dropped.
+func (w World) buildIslands(edges []islandEdge) []*Island {
+	uf := newUnionFind(len(w.bodies))
+
+	for _, edge := range edges {
+		if joinsIslands(edge.bodyA) && joinsIslands(edge.bodyB) {
+			uf.union(w.bodyIndex[edge.bodyA], w.bodyIndex[edge.bodyB])
+		}
+	}
+
+	islands := []*Island{}
+	byRoot := map[int]*Island{}
+
+	for i, body := range w.bodies {
+		if !joinsIslands(body) {
+			continue
+		}
+
+		root := uf.find(i)
+
+		island, ok := byRoot[root]
+		if !ok {
+			island = &Island{}
+			byRoot[root] = island
+			islands = append(islands, island)
+		}
+
+		island.Bodies = append(island.Bodies, body)
+	}
+
+	for _, edge := range edges {
+		body := edge.bodyA
+		if !joinsIslands(body) {
+			body = edge.bodyB
+		}
+
+		if !joinsIslands(body) {
+			continue
+		}
+
+		island := byRoot[uf.find(w.bodyIndex[body])]
+
+		if edge.contact != nil {
+			island.Contacts = append(island.Contacts, edge.contact)
+		}
+
+		if edge.joint != nil {
+			island.Joints = append(island.Joints, edge.joint)
+			island.constraints = append(island.constraints, edge.joint)
+		}
+
+		for _, pc := range edge.pens {
+			island.constraints = append(island.constraints, pc)
+		}
+	}
+
+	return islands
+}
+
+func joinsIslands(body entitysubset.RigidBodyFace) bool {
+	return body.BodyType() == physics_components.DynamicBody && body.Awake()
+}
@@ -0,0 +1,105 @@
+package world
+
+import (
+	entitysubset "github.com/kainn9/tteokbokki/physics/entity_subset"
+	"github.com/kainn9/tteokbokki/physics/solver"
+)
+
+// Both bodies must already be in the world, or be queued before the
+// joint when added during Step. Joints added during Step are queued
+// like bodies.
+func (w *World) AddJoint(joint solver.JointFace) {
+	if w.locked {
+		w.pending = append(w.pending, pendingChange{joint: joint})
+		return
+	}
+
+	if !w.hasBody(joint.BodyA()) || !w.hasBody(joint.BodyB()) {
+		panic("world: joint bodies must be added to the world first")
+	}
+
+	w.joints = append(w.joints, joint)
+}
+
+func (w *World) RemoveJoint(joint solver.JointFace) {
+	if w.locked {
+		w.pending = append(w.pending, pendingChange{joint: joint, remove: true})
+		return
+	}
+
+	w.joints = removeEntity(w.joints, joint)
+}
+
+func (w World) hasBody(body entitysubset.RigidBodyFace) bool {
+	_, ok := w.bodyIndex[body]
+	return ok
+}
+
+func (w World) Joints() []solver.JointFace {
+	return w.joints
+}
+
+// Removes every joint attached to body.
+func (w *World) removeJointsOf(body entitysubset.RigidBodyFace) {
+	attached := []solver.JointFace{}
+
+	for _, joint := range w.joints {
+		if joint.BodyA() == body || joint.BodyB() == body {
+			attached = append(attached, joint)
+		}
+	}
+
+	for _, joint := range attached {
+		w.RemoveJoint(joint)
+	}
+}
+
+// Rebuilt every step, so CollideConnected can be changed at any time.
+func (w *World) updateJointedPairs() {
+	for pair := range w.jointedPairs {
+		delete(w.jointedPairs, pair)
+	}
+
+	for _, joint := range w.joints {
+		if !joint.CollideConnected() {
+			w.jointedPairs[bodyPair{joint.BodyA(), joint.BodyB()}] = true
+		}
+	}
+}
+
+// True when a joint between the bodies turned their collisions off.
+func (w World) jointed(bodyA, bodyB entitysubset.RigidBodyFace) bool {
+	return w.jointedPairs[bodyPair{bodyA, bodyB}] || w.jointedPairs[bodyPair{bodyB, bodyA}]
+}
+
+// Wakes the bodies a joint connects and links them in the islands.
+func (w World) jointEdges() []islandEdge {
+	edges := make([]islandEdge, 0, len(w.joints))
+
+	for _, joint := range w.joints {
+		w.wakeTouching(joint.BodyA(), joint.BodyB())
+		edges = append(edges, islandEdge{bodyA: joint.BodyA(), bodyB: joint.BodyB(), joint: joint})
+	}
+
+	return edges
+}
+
+// Runs once the step is over, so OnBreak callbacks are free to change
+// the world.
+func (w *World) removeBrokenJoints() {
+	broken := []solver.BreakableJointFace{}
+
+	for _, joint := range w.joints {
+		if breakable, ok := joint.(solver.BreakableJointFace); ok && breakable.Broken() {
+			broken = append(broken, breakable)
+		}
+	}
+
+	for _, joint := range broken {
+		w.RemoveJoint(joint)
+
+		if onBreak := joint.OnBreak(); onBreak != nil {
+			onBreak(joint)
+		}
+	}
+}
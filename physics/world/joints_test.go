@@ -0,0 +1,24 @@
+package world
+
+import (
+	"testing"
+
+	"github.com/kainn9/tteokbokki/physics/solver"
+	"github.com/kainn9/tteokbokki/vector"
+)
+
+func TestAddJointPanicsForBodiesNotInWorld(t *testing.T) {
+	w := NewWorld(0)
+
+	bodyA := newBox(100, 100, 10, 10, 0)
+	bodyB := newBox(200, 100, 10, 10, 1)
+	w.Add(bodyA)
+
+	defer func() {
+		if recover() == nil {
+			t.Error("expected AddJoint to panic")
+		}
+	}()
+
+	w.AddJoint(solver.NewDistanceJoint(bodyA, bodyB, vector.NewVec2(0, 0), vector.NewVec2(0, 0)))
+}
@@ -0,0 +1,53 @@
+package world
+
+import (
+	"math"
+
+	"github.com/kainn9/tteokbokki/vector"
+)
+
+// Disables contacts with one way platforms unless the other body is
+// on the solid side. Once a body starts passing through it keeps
+// passing until the pair stops touching, so bodies that start a step
+// inside the platform are not popped out halfway through.
+func (w World) applyOneWay(contact *Contact) {
+	platform := contact.BodyA
+	// Normals point from A to B, flip them to point away from the platform.
+	sign := 1.0
+
+	if platform.OneWay() == nil {
+		platform = contact.BodyB
+		sign = -1
+	}
+
+	passDir := platform.OneWay()
+	if passDir == nil {
+		return
+	}
+
+	pair := bodyPair{contact.BodyA, contact.BodyB}
+
+	if w.oneWayPassing[pair] {
+		contact.Enabled = false
+		return
+	}
+
+	// Snapped so landing on a corner still counts as landing on top, the
+	// manifold keeps its real normals for resolving.
+	normal := snapToAxis(contact.Manifold.Normal)
+
+	// Touching the sides or the underside of the platform.
+	if normal.Scale(sign).ScalarProduct(passDir) <= 0 {
+		w.oneWayPassing[pair] = true
+		contact.Enabled = false
+	}
+}
+
+// Copy of normal along whichever axis it is closest to.
+func snapToAxis(normal vector.Vec2Face) vector.Vec2Face {
+	if math.Abs(normal.X()) < math.Abs(normal.Y()) {
+		return vector.NewVec2(0, math.Copysign(1, normal.Y()))
+	}
+
+	return vector.NewVec2(math.Copysign(1, normal.X()), 0)
+}
@@ -0,0 +1,53 @@
+package world
+
+import entitysubset "github.com/kainn9/tteokbokki/physics/entity_subset"
+
+type bodyPair struct {
+	bodyA, bodyB entitysubset.RigidBodyFace
+}
+
+// Pairs found during the current and previous steps, kept in the order
+// they were found so begin/end events are deterministic.
+type touchingPairs struct {
+	previous, current             []bodyPair
+	previousLookup, currentLookup map[bodyPair]bool
+}
+
+func newTouchingPairs() *touchingPairs {
+	return &touchingPairs{
+		previousLookup: make(map[bodyPair]bool),
+		currentLookup:  make(map[bodyPair]bool),
+	}
+}
+
+// Returns true when the pair was not touching last step.
+func (tp *touchingPairs) add(pair bodyPair) bool {
+	tp.current = append(tp.current, pair)
+	tp.currentLookup[pair] = true
+
+	return !tp.previousLookup[pair]
+}
+
+// Calls fn for pairs from the previous step that were not found again,
+// then starts tracking the next step.
+func (tp *touchingPairs) endStep(fn func(pair bodyPair)) {
+	for _, pair := range tp.previous {
+		if !tp.currentLookup[pair] {
+			fn(pair)
+		}
+	}
+
+	tp.previous, tp.current = tp.current, tp.previous[:0]
+	tp.previousLookup, tp.currentLookup = tp.currentLookup, tp.previousLookup
+
+	for pair := range tp.currentLookup {
+		delete(tp.currentLookup, pair)
+	}
+}
+
+// Carries a pair from the previous step over without sending events.
+func (tp *touchingPairs) keep(pair bodyPair) {
+	if tp.previousLookup[pair] {
+		tp.add(pair)
+	}
+}
@@ -0,0 +1,70 @@
+package world
+
+import (
+	"math"
+	"testing"
+
+	"github.com/kainn9/tteokbokki/physics/solver"
+	"github.com/kainn9/tteokbokki/vector"
+)
+
+// Distance of B's anchor off the joint's axis.
+func prismaticAxisError(joint solver.PrismaticJointFace) float64 {
+	anchorA := worldAnchor(joint.BodyA(), joint.LocalAnchorA())
+	anchorB := worldAnchor(joint.BodyB(), joint.LocalAnchorB())
+	axis := joint.LocalAxisA().Rotate(joint.BodyA().Rotation())
+
+	return anchorB.Sub(anchorA).ScalarProduct(axis.Perpendicular())
+}
+
+func TestPrismaticJointStaysOnAxis(t *testing.T) {
+	forEachSolver(t, func(t *testing.T, w WorldFace) {
+		carrier := newBox(500, 100, 40, 40, 2)
+		slider := newBox(560, 100, 10, 10, 1)
+		w.Add(carrier)
+		w.Add(slider)
+
+		joint := solver.NewPrismaticJoint(carrier, slider, vector.NewVec2(500, 100), vector.NewVec2(1, 0))
+		w.AddJoint(joint)
+
+		// Spinning the carrier swings the axis around, flinging the
+		// slider outwards along it.
+		carrier.SetAngularVel(3)
+
+		stepWorld(w, 600, func(step int) {
+			if axisError := math.Abs(prismaticAxisError(joint)); axisError > 1 {
+				t.Fatalf("step %d: expected the slider to stay within 1 of the axis, drifted %.2f off it", step, axisError)
+			}
+
+			angle := slider.Rotation() - carrier.Rotation() - joint.ReferenceAngle()
+			if math.Abs(angle) > 0.02 {
+				t.Fatalf("step %d: expected the relative rotation to stay locked, got %.3f", step, angle)
+			}
+		})
+	})
+}
+
+func TestPrismaticJointLimitsHoldTranslation(t *testing.T) {
+	forEachSolver(t, func(t *testing.T, w WorldFace) {
+		rail := newBox(300, 100, 10, 10, 0)
+		slider := newBox(300, 100, 20, 20, 1)
+		w.Add(rail)
+		w.Add(slider)
+
+		// Slopes down to the right, so gravity slides it to the upper limit.
+		joint := solver.NewPrismaticJoint(rail, slider, vector.NewVec2(300, 100), vector.NewVec2(1, 1))
+		joint.SetLimitEnabled(true)
+		joint.SetLimits(-50, 80)
+		w.AddJoint(joint)
+
+		stepWorld(w, 600, func(step int) {
+			if translation := joint.JointTranslation(); translation < -51 || translation > 81 {
+				t.Fatalf("step %d: expected the translation to stay in [-50, 80], got %.2f", step, translation)
+			}
+		})
+
+		if translation := joint.JointTranslation(); math.Abs(translation-80) > 1 {
+			t.Errorf("expected the slider to rest on the upper limit, got %.2f", translation)
+		}
+	})
+}
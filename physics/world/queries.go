@@ -0,0 +1,202 @@
+package world
+
+import (
+	"sort"
+
+	"github.com/kainn9/tteokbokki/physics/broadphase"
+	physics_components "github.com/kainn9/tteokbokki/physics/components"
+	"github.com/kainn9/tteokbokki/physics/detector"
+	entitysubset "github.com/kainn9/tteokbokki/physics/entity_subset"
+	transform_components "github.com/kainn9/tteokbokki/transform/components"
+	"github.com/kainn9/tteokbokki/vector"
+)
+
+type RaycastMode int
+
+const (
+	// Only the nearest hit.
+	RaycastClosest RaycastMode = iota
+	// Every hit, nearest first.
+	RaycastAll
+	// Whichever hit is found first, cheapest for line of sight checks.
+	RaycastAny
+)
+
+// Query filter that only keeps bodies the given filter can collide with.
+func FilterQuery(filter physics_components.Filter) func(entitysubset.RigidBodyFace) bool {
+	return func(body entitysubset.RigidBodyFace) bool {
+		return filter.ShouldCollide(body.Filter())
+	}
+}
+
+type RaycastResult struct {
+	Body entitysubset.RigidBodyFace
+	transform_components.RaycastHit
+}
+
+// Bodies the filter returns false for are skipped, filter may be nil.
+func (w World) Raycast(
+	origin, dir vector.Vec2Face,
+	maxDist float64,
+	mode RaycastMode,
+	filter func(entitysubset.RigidBodyFace) bool,
+) []RaycastResult {
+	results := []RaycastResult{}
+
+	if !(maxDist > 0) || dir.MagSquared() == 0 {
+		return results
+	}
+
+	end := origin.Add(dir.Norm().Scale(maxDist))
+
+	for _, body := range w.broadphase.QuerySegment(origin, end) {
+		if filter != nil && !filter(body) {
+			continue
+		}
+
+		hit, result := detector.Raycast(body, origin, dir, maxDist)
+		if !hit {
+			continue
+		}
+
+		results = append(results, RaycastResult{body, result})
+
+		if mode == RaycastAny {
+			return results
+		}
+	}
+
+	sort.SliceStable(results, func(i, j int) bool {
+		return results[i].Fraction < results[j].Fraction
+	})
+
+	if mode == RaycastClosest && len(results) > 1 {
+		return results[:1]
+	}
+
+	return results
+}
+
+type ShapeCastResult struct {
+	Body entitysubset.RigidBodyFace
+	detector.ShapeCastHit
+}
+
+// Sweeps moving along motion against every other body, using the same
+// modes as Raycast. Moving does not need to be part of the world.
+func (w World) ShapeCast(
+	moving entitysubset.RigidBodyFace,
+	motion vector.Vec2Face,
+	mode RaycastMode,
+	filter func(entitysubset.RigidBodyFace) bool,
+) []ShapeCastResult {
+	results := []ShapeCastResult{}
+
+	bounds := broadphase.NewBodyAABB(moving)
+	swept := bounds.Union(broadphase.NewAABB(
+		bounds.MinX+motion.X(), bounds.MinY+motion.Y(),
+		bounds.MaxX+motion.X(), bounds.MaxY+motion.Y(),
+	))
+
+	for _, body := range w.broadphase.Query(swept) {
+		if body == moving || (filter != nil && !filter(body)) {
+			continue
+		}
+
+		hit, result := detector.ShapeCast(moving, body, motion)
+		if !hit {
+			continue
+		}
+
+		results = append(results, ShapeCastResult{body, result})
+
+		if mode == RaycastAny {
+			return results
+		}
+	}
+
+	sort.SliceStable(results, func(i, j int) bool {
+		return results[i].Fraction < results[j].Fraction
+	})
+
+	if mode == RaycastClosest && len(results) > 1 {
+		return results[:1]
+	}
+
+	return results
+}
+
+// Bodies containing the point, in broadphase order.
+func (w World) QueryPoint(
+	point vector.Vec2Face,
+	filter func(entitysubset.RigidBodyFace) bool,
+) []entitysubset.RigidBodyFace {
+	bodies := []entitysubset.RigidBodyFace{}
+	bounds := broadphase.NewAABB(point.X(), point.Y(), point.X(), point.Y())
+
+	for _, body := range w.broadphase.Query(bounds) {
+		if filter != nil && !filter(body) {
+			continue
+		}
+
+		if detector.ContainsPoint(body, point) {
+			bodies = append(bodies, body)
+		}
+	}
+
+	return bodies
+}
+
+// Bodies whose shape(not just bounds) overlaps the rectangle.
+func (w World) QueryAABB(
+	aabb broadphase.AABB,
+	filter func(entitysubset.RigidBodyFace) bool,
+) []entitysubset.RigidBodyFace {
+	trans := transform_components.NewTransform(
+		aabb.MinX+aabb.Width()/2,
+		aabb.MinY+aabb.Height()/2,
+		0,
+	)
+	region := entitysubset.NewRigidBody(
+		trans,
+		transform_components.NewPolygonRectangleShape(aabb.Width(), aabb.Height()),
+		nil,
+	)
+	region.UpdateWorldVertices()
+
+	return w.queryRegion(region, aabb, filter)
+}
+
+func (w World) QueryCircle(
+	center vector.Vec2Face,
+	radius float64,
+	filter func(entitysubset.RigidBodyFace) bool,
+) []entitysubset.RigidBodyFace {
+	region := entitysubset.NewRigidBody(
+		transform_components.NewTransform(center.X(), center.Y(), 0),
+		transform_components.NewCircleShape(radius),
+		nil,
+	)
+
+	return w.queryRegion(region, broadphase.NewBodyAABB(region), filter)
+}
+
+func (w World) queryRegion(
+	region entitysubset.RigidBodyFace,
+	bounds broadphase.AABB,
+	filter func(entitysubset.RigidBodyFace) bool,
+) []entitysubset.RigidBodyFace {
+	bodies := []entitysubset.RigidBodyFace{}
+
+	for _, body := range w.broadphase.Query(bounds) {
+		if filter != nil && !filter(body) {
+			continue
+		}
+
+		if detector.Overlaps(region, body) {
+			bodies = append(bodies, body)
+		}
+	}
+
+	return bodies
+}
@@ -0,0 +1,73 @@
+package world
+
+import (
+	"math"
+	"testing"
+
+	"github.com/kainn9/tteokbokki/physics/broadphase"
+	entitysubset "github.com/kainn9/tteokbokki/physics/entity_subset"
+	"github.com/kainn9/tteokbokki/vector"
+)
+
+// A circle at (100, 100), a box at (200, 100) turned 45 degrees and a
+// box at (300, 100), none of them moving.
+func newQueryWorld() (w WorldFace, circle, diamond, box entitysubset.RigidBodyFace) {
+	w = NewWorld(0)
+
+	circle = newCircle(100, 100, 20, 0)
+	diamond = newBox(200, 100, 40, 40, 0)
+	diamond.SetRotation(math.Pi / 4)
+	diamond.UpdateWorldVertices()
+	box = newBox(300, 100, 40, 40, 0)
+
+	w.Add(circle)
+	w.Add(diamond)
+	w.Add(box)
+
+	return w, circle, diamond, box
+}
+
+// Results come back in broadphase order, so only which bodies were
+// found is compared.
+func expectBodies(t *testing.T, query string, got []entitysubset.RigidBodyFace, expected ...entitysubset.RigidBodyFace) {
+	t.Helper()
+
+	found := map[entitysubset.RigidBodyFace]bool{}
+	for _, body := range got {
+		found[body] = true
+	}
+
+	if len(got) != len(expected) || len(found) != len(got) {
+		t.Errorf("%s: expected %d bodies, got %d", query, len(expected), len(got))
+		return
+	}
+
+	for _, body := range expected {
+		if !found[body] {
+			t.Errorf("%s: expected the body at %v", query, body.Position())
+		}
+	}
+}
+
+func TestQueryPoint(t *testing.T) {
+	w, circle, diamond, box := newQueryWorld()
+
+	expectBodies(t, "circle center", w.QueryPoint(vector.NewVec2(100, 100), nil), circle)
+	expectBodies(t, "box edge", w.QueryPoint(vector.NewVec2(320, 100), nil), box)
+	expectBodies(t, "diamond tip", w.QueryPoint(vector.NewVec2(200, 127), nil), diamond)
+
+	// Inside the bounds of both, but outside their shapes.
+	expectBodies(t, "circle bounds corner", w.QueryPoint(vector.NewVec2(118, 118), nil))
+	expectBodies(t, "diamond bounds corner", w.QueryPoint(vector.NewVec2(225, 125), nil))
+}
+
+func TestQueryAABBAndCircle(t *testing.T) {
+	w, circle, diamond, box := newQueryWorld()
+
+	expectBodies(t, "aabb over everything", w.QueryAABB(broadphase.NewAABB(0, 0, 400, 200), nil), circle, diamond, box)
+	expectBodies(t, "aabb past the circle", w.QueryAABB(broadphase.NewAABB(116, 116, 130, 130), nil))
+	expectBodies(t, "aabb between", w.QueryAABB(broadphase.NewAABB(240, 50, 270, 150), nil))
+
+	expectBodies(t, "circle reaching both boxes", w.QueryCircle(vector.NewVec2(250, 100), 30, nil), diamond, box)
+	expectBodies(t, "circle by the diamonds corner", w.QueryCircle(vector.NewVec2(230, 130), 5, nil))
+}
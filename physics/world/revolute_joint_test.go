@@ -0,0 +1,107 @@
+package world
+
+import (
+	"math"
+	"testing"
+
+	entitysubset "github.com/kainn9/tteokbokki/physics/entity_subset"
+	"github.com/kainn9/tteokbokki/physics/solver"
+	"github.com/kainn9/tteokbokki/vector"
+)
+
+// Where a local anchor on body is in world space.
+func worldAnchor(body entitysubset.RigidBodyFace, localAnchor vector.Vec2Face) vector.Vec2Face {
+	return body.Position().Add(localAnchor.Rotate(body.Rotation()))
+}
+
+// Frame is static, the door hangs off its right side from a hinge at (105, 100).
+func newDoor(w WorldFace) (frame, door entitysubset.RigidBodyFace, hinge solver.RevoluteJointFace) {
+	frame = newBox(100, 100, 10, 10, 0)
+	door = newBox(135, 100, 60, 10, 1)
+	w.Add(frame)
+	w.Add(door)
+
+	hinge = solver.NewRevoluteJoint(frame, door, vector.NewVec2(105, 100))
+	w.AddJoint(hinge)
+
+	return frame, door, hinge
+}
+
+func TestRevoluteJointAnchorDriftStaysBounded(t *testing.T) {
+	forEachSolver(t, func(t *testing.T, w WorldFace) {
+		frame, door, hinge := newDoor(w)
+
+		stepWorld(w, 600, func(step int) {
+			anchorA := worldAnchor(frame, hinge.LocalAnchorA())
+			anchorB := worldAnchor(door, hinge.LocalAnchorB())
+
+			if drift := anchorB.Sub(anchorA).Mag(); drift > 1 {
+				t.Fatalf("step %d: expected the anchors to stay within 1 of each other, drifted %.2f apart", step, drift)
+			}
+		})
+	})
+}
+
+func TestRevoluteJointLimitsHoldAngle(t *testing.T) {
+	forEachSolver(t, func(t *testing.T, w WorldFace) {
+		_, _, hinge := newDoor(w)
+
+		hinge.SetLimitEnabled(true)
+		hinge.SetLimits(-math.Pi/4, math.Pi/4)
+
+		stepWorld(w, 600, func(step int) {
+			if angle := hinge.JointAngle(); angle < -math.Pi/4-0.02 || angle > math.Pi/4+0.02 {
+				t.Fatalf("step %d: expected the angle to stay within the limits, got %.3f", step, angle)
+			}
+		})
+
+		// Gravity swings the door down onto the upper limit.
+		if angle := hinge.JointAngle(); math.Abs(angle-math.Pi/4) > 0.02 {
+			t.Errorf("expected the door to rest on the upper limit, got %.3f", angle)
+		}
+	})
+}
+
+func TestRevoluteJointMotorReachesSpeed(t *testing.T) {
+	forEachSolver(t, func(t *testing.T, w WorldFace) {
+		axle := newBox(300, 100, 10, 10, 0)
+		wheel := newBox(300, 100, 40, 40, 1)
+		w.Add(axle)
+		w.Add(wheel)
+
+		motor := solver.NewRevoluteJoint(axle, wheel, vector.NewVec2(300, 100))
+		motor.SetMotorEnabled(true)
+		motor.SetMotorSpeed(2)
+		motor.SetMaxMotorTorque(1e6)
+		w.AddJoint(motor)
+
+		// Plenty of torque, so it holds the speed from the first step on.
+		stepWorld(w, 120, func(step int) {
+			if speed := motor.JointSpeed(); math.Abs(speed-2) > 0.01 {
+				t.Fatalf("step %d: expected the wheel to turn at 2, got %.3f", step, speed)
+			}
+		})
+	})
+}
+
+func TestRevoluteJointMotorTorqueIsCapped(t *testing.T) {
+	forEachSolver(t, func(t *testing.T, w WorldFace) {
+		_, door, hinge := newDoor(w)
+
+		// Far too weak to hold the door up.
+		hinge.SetMotorEnabled(true)
+		hinge.SetMotorSpeed(0)
+		hinge.SetMaxMotorTorque(10)
+
+		maxImpulse := 10 * testDt
+		stepWorld(w, 600, func(step int) {
+			if impulse := math.Abs(hinge.MotorImpulse()); impulse > maxImpulse+1e-9 {
+				t.Fatalf("step %d: expected the motor impulse to stay under %.3f, got %.3f", step, maxImpulse, impulse)
+			}
+		})
+
+		if door.Position().Y() <= 100 {
+			t.Errorf("expected the door to swing down past the weak motor, it is at %v", door.Position())
+		}
+	})
+}
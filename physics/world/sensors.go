@@ -0,0 +1,58 @@
+package world
+
+import (
+	entitysubset "github.com/kainn9/tteokbokki/physics/entity_subset"
+)
+
+// Receives overlaps between sensors and other bodies. When both bodies
+// are sensors, the one added to the world first is passed as sensor.
+// Events are sent during Step, so Add/Remove calls are queued.
+type SensorListenerFace interface {
+	BeginOverlap(sensor, other entitysubset.RigidBodyFace)
+	// Sent every step after BeginOverlap while the overlap lasts.
+	StayOverlap(sensor, other entitysubset.RigidBodyFace)
+	// Also sent the step after either body is removed from the world.
+	EndOverlap(sensor, other entitysubset.RigidBodyFace)
+}
+
+func (w World) SensorListener() SensorListenerFace {
+	return w.sensorListener
+}
+
+func (w *World) SetSensorListener(listener SensorListenerFace) {
+	w.sensorListener = listener
+}
+
+// Sends the overlap events for a pair where at least one body is a sensor.
+func (w World) handleSensorPair(bodyA, bodyB entitysubset.RigidBodyFace, overlapping bool) {
+	if !overlapping {
+		return
+	}
+
+	// The sensor is always stored as bodyA.
+	pair := bodyPair{bodyA, bodyB}
+	if !bodyA.Sensor() {
+		pair = bodyPair{bodyB, bodyA}
+	}
+
+	isNew := w.sensorOverlaps.add(pair)
+
+	if w.sensorListener == nil {
+		return
+	}
+
+	if isNew {
+		w.sensorListener.BeginOverlap(pair.bodyA, pair.bodyB)
+	} else {
+		w.sensorListener.StayOverlap(pair.bodyA, pair.bodyB)
+	}
+}
+
+// Ends every overlap from the previous step that was not found again.
+func (w World) endSensorOverlaps() {
+	w.sensorOverlaps.endStep(func(pair bodyPair) {
+		if w.sensorListener != nil {
+			w.sensorListener.EndOverlap(pair.bodyA, pair.bodyB)
+		}
+	})
+}
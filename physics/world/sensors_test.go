@@ -0,0 +1,128 @@
+package world
+
+import (
+	"math"
+	"testing"
+
+	physics_components "github.com/kainn9/tteokbokki/physics/components"
+	entitysubset "github.com/kainn9/tteokbokki/physics/entity_subset"
+	"github.com/kainn9/tteokbokki/physics/factory"
+	"github.com/kainn9/tteokbokki/vector"
+)
+
+// Counts every event sent for each body passing through a sensor.
+type overlapRecorder struct {
+	begins, stays, ends map[entitysubset.RigidBodyFace]int
+}
+
+func newOverlapRecorder() *overlapRecorder {
+	return &overlapRecorder{
+		begins: map[entitysubset.RigidBodyFace]int{},
+		stays:  map[entitysubset.RigidBodyFace]int{},
+		ends:   map[entitysubset.RigidBodyFace]int{},
+	}
+}
+
+func (r *overlapRecorder) BeginOverlap(sensor, other entitysubset.RigidBodyFace) {
+	r.begins[other]++
+}
+
+func (r *overlapRecorder) StayOverlap(sensor, other entitysubset.RigidBodyFace) {
+	r.stays[other]++
+}
+
+func (r *overlapRecorder) EndOverlap(sensor, other entitysubset.RigidBodyFace) {
+	r.ends[other]++
+}
+
+func TestSensorReportsOverlapsWithoutResolving(t *testing.T) {
+	forEachSolver(t, func(t *testing.T, w WorldFace) {
+		recorder := newOverlapRecorder()
+		w.SetSensorListener(recorder)
+
+		sensor := newBox(200, 200, 100, 40, 0)
+		sensor.SetSensor(true)
+		w.Add(sensor)
+
+		falling := newBox(200, 150, 20, 20, 1)
+		w.Add(falling)
+
+		gravity := factory.Forces.DEFAULT_GRAVITY * factory.Forces.PIXELS_PER_METER
+
+		inside := 0
+		stepWorld(w, 60, func(step int) {
+			// Never slowed down, it only feels gravity.
+			expected := gravity * testDt * float64(step+1)
+			if vel := falling.Vel().Y(); math.Abs(vel-expected) > 1e-6 {
+				t.Fatalf("step %d: expected to fall freely at %.2f, got %.2f", step, expected, vel)
+			}
+
+			// Overlapping from y 170 to 230.
+			if y := falling.Position().Y(); y > 171 && y < 229 {
+				inside++
+
+				if recorder.begins[falling] != 1 || recorder.ends[falling] != 0 {
+					t.Fatalf("step %d: expected one BeginOverlap and no EndOverlap at y %.1f", step, y)
+				}
+			}
+		})
+
+		if recorder.ends[falling] != 1 {
+			t.Errorf("expected one EndOverlap once the box fell out, got %d", recorder.ends[falling])
+		}
+
+		// Every step inside but the first, which sent BeginOverlap instead.
+		if stays := recorder.stays[falling]; stays < inside-2 || stays > inside {
+			t.Errorf("expected about %d StayOverlap, got %d", inside-1, stays)
+		}
+	})
+}
+
+func TestSensorEndsOverlapWhenRemoved(t *testing.T) {
+	w := NewWorld(0)
+	recorder := newOverlapRecorder()
+	w.SetSensorListener(recorder)
+
+	sensor := newBox(200, 200, 100, 40, 0)
+	sensor.SetSensor(true)
+	w.Add(sensor)
+
+	box := newBox(200, 200, 20, 20, 1)
+	w.Add(box)
+
+	stepWorld(w, 2, nil)
+	w.Remove(box)
+	stepWorld(w, 1, nil)
+
+	if recorder.begins[box] != 1 || recorder.ends[box] != 1 {
+		t.Errorf("expected one begin and one end, got %d and %d", recorder.begins[box], recorder.ends[box])
+	}
+}
+
+func TestKinematicBodyPassesThroughStaticSensor(t *testing.T) {
+	forEachSolver(t, func(t *testing.T, w WorldFace) {
+		recorder := newOverlapRecorder()
+		w.SetSensorListener(recorder)
+
+		sensor := newBox(200, 100, 40, 40, 0)
+		sensor.SetSensor(true)
+		w.Add(sensor)
+
+		mover := newBox(100, 100, 20, 20, 0)
+		mover.SetBodyType(physics_components.KinematicBody)
+		mover.SetVel(vector.NewVec2(300, 0))
+		w.Add(mover)
+
+		stepWorld(w, 60, func(step int) {
+			// Overlapping from x 170 to 230, give or take the mover's speed.
+			x := mover.Position().X()
+			if x > 175 && x < 225 && recorder.begins[mover] != 1 {
+				t.Fatalf("step %d: expected BeginOverlap once the mover is at x %.1f", step, x)
+			}
+		})
+
+		if recorder.begins[mover] != 1 || recorder.ends[mover] != 1 {
+			t.Errorf("expected one begin and one end, got %d and %d", recorder.begins[mover], recorder.ends[mover])
+		}
+	})
+}
@@ -0,0 +1,116 @@
+package world
+
+import (
+	"math"
+
+	physics_components "github.com/kainn9/tteokbokki/physics/components"
+	entitysubset "github.com/kainn9/tteokbokki/physics/entity_subset"
+)
+
+// Tolerances are checked against each bodies velocity at the end of the
+// step. The ProjectionImpulseSolver resolves one pair at a time, so bodies
+// stacked on each other keep some of the gravity pushed down into the pair
+// below and only the SequentialImpulseSolver lets stacks fall asleep.
+type sleepConfig struct {
+	// Speed below which a body counts as resting, in pixels per second.
+	LINEAR_TOLERANCE float64
+	// Angular speed below which a body counts as resting, in radians per second.
+	ANGULAR_TOLERANCE float64
+	// How long a body has to rest before falling asleep, in seconds.
+	TIME_TO_SLEEP float64
+}
+
+var SleepConfig = &sleepConfig{
+	LINEAR_TOLERANCE:  5.0,
+	ANGULAR_TOLERANCE: 0.05,
+	TIME_TO_SLEEP:     0.5,
+}
+
+func (w World) SleepingEnabled() bool {
+	return w.sleepingEnabled
+}
+
+// Disabling sleep wakes every body.
+func (w *World) SetSleepingEnabled(enabled bool) {
+	w.sleepingEnabled = enabled
+
+	if enabled {
+		return
+	}
+
+	for _, body := range w.bodies {
+		if !body.Awake() {
+			body.SetAwake(true)
+		}
+	}
+}
+
+// Puts whole islands to sleep once every body in them has been
+// resting long enough, so stacks fall asleep together.
+func (w World) updateSleep(dt float64) {
+	if !w.sleepingEnabled {
+		return
+	}
+
+	linearTolerance := SleepConfig.LINEAR_TOLERANCE * SleepConfig.LINEAR_TOLERANCE
+	angularTolerance := SleepConfig.ANGULAR_TOLERANCE * SleepConfig.ANGULAR_TOLERANCE
+
+	for _, island := range w.islands {
+		minSleepTime := math.Inf(1)
+
+		for _, body := range island.Bodies {
+			resting := body.Vel().MagSquared() <= linearTolerance &&
+				body.AngularVel()*body.AngularVel() <= angularTolerance
+
+			if !body.CanSleep() || !resting {
+				body.SetSleepTime(0)
+			} else {
+				body.SetSleepTime(body.SleepTime() + dt)
+			}
+
+			minSleepTime = math.Min(minSleepTime, body.SleepTime())
+		}
+
+		if minSleepTime < SleepConfig.TIME_TO_SLEEP {
+			continue
+		}
+
+		for _, body := range island.Bodies {
+			body.SetAwake(false)
+		}
+	}
+}
+
+// Wakes a sleeping body touched by one that is actually moving. Bodies
+// that are awake but resting leave sleeping ones alone, so a stack can
+// fall asleep one body at a time.
+func (w World) wakeTouching(bodyA, bodyB entitysubset.RigidBodyFace) {
+	if !bodyA.Awake() && isMoving(bodyB) {
+		bodyA.SetAwake(true)
+	}
+
+	if !bodyB.Awake() && isMoving(bodyA) {
+		bodyB.SetAwake(true)
+	}
+}
+
+func isMoving(body entitysubset.RigidBodyFace) bool {
+	switch body.BodyType() {
+	case physics_components.DynamicBody:
+		return body.Awake() && body.SleepTime() == 0
+	case physics_components.KinematicBody:
+		return body.Vel().MagSquared() > 0 || body.AngularVel() != 0
+	}
+
+	return false
+}
+
+// True for pairs where neither body can move, which are skipped
+// without ending their contact.
+func isResting(bodyA, bodyB entitysubset.RigidBodyFace) bool {
+	return !canMove(bodyA) && !canMove(bodyB)
+}
+
+func canMove(body entitysubset.RigidBodyFace) bool {
+	return body.BodyType() != physics_components.StaticBody && body.Awake()
+}
@@ -0,0 +1,85 @@
+package world
+
+import (
+	"testing"
+
+	entitysubset "github.com/kainn9/tteokbokki/physics/entity_subset"
+	"github.com/kainn9/tteokbokki/physics/factory"
+)
+
+// Steps until every body is asleep, failing if that takes more than
+// three seconds or a body drifts off where it rests.
+func stepUntilAsleep(t *testing.T, w WorldFace, bodies ...entitysubset.RigidBodyFace) {
+	t.Helper()
+
+	for i := 0; i < 180; i++ {
+		w.Step(testDt)
+
+		asleep := true
+		for _, body := range bodies {
+			asleep = asleep && !body.Awake()
+
+			if speed := body.Vel().Mag(); i > 60 && speed > 50 {
+				t.Fatalf("step %d: body at %v still moving at %.1f", i, body.Position(), speed)
+			}
+		}
+
+		if asleep {
+			return
+		}
+	}
+
+	for _, body := range bodies {
+		if body.Awake() {
+			t.Errorf("body at %v still awake, vel %v angular vel %.3f", body.Position(), body.Vel(), body.AngularVel())
+		}
+	}
+}
+
+func TestRestingBoxFallsAsleep(t *testing.T) {
+	forEachSolver(t, func(t *testing.T, w WorldFace) {
+		addFloor(w)
+
+		box := newBox(200, 270, 40, 40, 1)
+		w.Add(box)
+
+		stepUntilAsleep(t, w, box)
+	})
+}
+
+// Only the SequentialImpulseSolver settles stacks, see SleepConfig.
+func TestRestingStackFallsAsleep(t *testing.T) {
+	w := NewWorld(factory.Forces.DEFAULT_GRAVITY)
+	w.SetSolver(SequentialImpulseSolver)
+	addFloor(w)
+
+	boxes := make([]entitysubset.RigidBodyFace, 3)
+	for i := range boxes {
+		boxes[i] = newBox(200, 270-float64(i)*41, 40, 40, 1)
+		w.Add(boxes[i])
+	}
+
+	stepUntilAsleep(t, w, boxes...)
+}
+
+func TestSleepingBoxWakesWhenHit(t *testing.T) {
+	forEachSolver(t, func(t *testing.T, w WorldFace) {
+		addFloor(w)
+
+		box := newBox(200, 270, 40, 40, 1)
+		w.Add(box)
+		stepUntilAsleep(t, w, box)
+
+		falling := newBox(200, 150, 20, 20, 1)
+		w.Add(falling)
+
+		woke := false
+		stepWorld(w, 60, func(step int) {
+			woke = woke || box.Awake()
+		})
+
+		if !woke {
+			t.Error("expected the box to wake when the falling one lands on it")
+		}
+	})
+}
@@ -0,0 +1,44 @@
+package world
+
+// Disjoint sets over 0..n-1, with path halving and union by size.
+type unionFind struct {
+	parent, size []int
+}
+
+func newUnionFind(n int) *unionFind {
+	uf := &unionFind{
+		parent: make([]int, n),
+		size:   make([]int, n),
+	}
+
+	for i := range uf.parent {
+		uf.parent[i] = i
+		uf.size[i] = 1
+	}
+
+	return uf
+}
+
+func (uf *unionFind) find(i int) int {
+	for uf.parent[i] != i {
+		uf.parent[i] = uf.parent[uf.parent[i]]
+		i = uf.parent[i]
+	}
+
+	return i
+}
+
+func (uf *unionFind) union(a, b int) {
+	rootA, rootB := uf.find(a), uf.find(b)
+
+	if rootA == rootB {
+		return
+	}
+
+	if uf.size[rootA] < uf.size[rootB] {
+		rootA, rootB = rootB, rootA
+	}
+
+	uf.parent[rootB] = rootA
+	uf.size[rootA] += uf.size[rootB]
+}
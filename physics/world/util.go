@@ -0,0 +1,13 @@
+package world
+
+// Removes the first matching entity while keeping the
+// order of the rest, so stepping stays deterministic.
+func removeEntity[T comparable](entities []T, entity T) []T {
+	for i, e := range entities {
+		if e == entity {
+			return append(entities[:i], entities[i+1:]...)
+		}
+	}
+
+	return entities
+}
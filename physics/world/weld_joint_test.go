@@ -0,0 +1,87 @@
+package world
+
+import (
+	"testing"
+
+	entitysubset "github.com/kainn9/tteokbokki/physics/entity_subset"
+	"github.com/kainn9/tteokbokki/physics/factory"
+	"github.com/kainn9/tteokbokki/physics/solver"
+	"github.com/kainn9/tteokbokki/vector"
+)
+
+// Wall is static, the beam sticks out of its right side.
+func newBeam(w WorldFace) (beam entitysubset.RigidBodyFace, weld solver.WeldJointFace) {
+	wall := newBox(100, 100, 10, 40, 0)
+	beam = newBox(135, 100, 60, 10, 1)
+	w.Add(wall)
+	w.Add(beam)
+
+	weld = solver.NewWeldJoint(wall, beam, vector.NewVec2(105, 100))
+	w.AddJoint(weld)
+
+	return beam, weld
+}
+
+func TestWeldJointReactionForceMatchesWeight(t *testing.T) {
+	forEachSolver(t, func(t *testing.T, w WorldFace) {
+		_, weld := newBeam(w)
+
+		// Holds the beam up against its weight.
+		weight := factory.Forces.DEFAULT_GRAVITY * factory.Forces.PIXELS_PER_METER
+		expected := vector.NewVec2(0, -weight)
+
+		stepWorld(w, 120, func(step int) {
+			if force := weld.ReactionForce(); force.Sub(expected).Mag() > weight*0.01 {
+				t.Fatalf("step %d: expected a reaction force of %v, got %v", step, expected, force)
+			}
+		})
+	})
+}
+
+func TestWeldJointBreaksOnce(t *testing.T) {
+	forEachSolver(t, func(t *testing.T, w WorldFace) {
+		beam, weld := newBeam(w)
+
+		// Holding the beam takes its weight times 30 pixels of lever.
+		weld.SetBreakTorque(5000)
+
+		breaks := 0
+		weld.SetOnBreak(func(joint solver.JointFace) {
+			breaks++
+
+			if joint != weld {
+				t.Error("expected OnBreak to be called with the broken joint")
+			}
+		})
+
+		// The weight is there from the first step, so it breaks straight away.
+		stepWorld(w, 120, func(step int) {
+			if breaks != 1 {
+				t.Fatalf("step %d: expected OnBreak to have been called once, got %d", step, breaks)
+			}
+
+			if len(w.Joints()) != 0 {
+				t.Fatalf("step %d: expected the broken joint to be removed from the world", step)
+			}
+		})
+
+		if beam.Position().Y() <= 150 {
+			t.Errorf("expected the beam to fall once the weld broke, it is at %v", beam.Position())
+		}
+	})
+}
+
+func TestWeldJointHoldsUnderThreshold(t *testing.T) {
+	forEachSolver(t, func(t *testing.T, w WorldFace) {
+		_, weld := newBeam(w)
+
+		weld.SetBreakForce(1000)
+		weld.SetBreakTorque(20000)
+
+		stepWorld(w, 120, func(step int) {
+			if weld.Broken() || len(w.Joints()) != 1 {
+				t.Fatalf("step %d: expected the weld to hold a load under its thresholds", step)
+			}
+		})
+	})
+}
@@ -0,0 +1,71 @@
+package world
+
+import (
+	"testing"
+
+	physics_components "github.com/kainn9/tteokbokki/physics/components"
+	entitysubset "github.com/kainn9/tteokbokki/physics/entity_subset"
+	"github.com/kainn9/tteokbokki/physics/factory"
+	"github.com/kainn9/tteokbokki/physics/pool"
+	"github.com/kainn9/tteokbokki/vector"
+)
+
+// A kinematic pusher swept into the top of a stack that has already
+// fallen asleep. It was added first, so its pair is handled before the
+// pairs inside the stack, which get woken up part way through a step.
+func newSleepingStackWorld(wp pool.WorkerPoolFace) (WorldFace, []entitysubset.RigidBodyFace) {
+	w := NewWorld(factory.Forces.DEFAULT_GRAVITY)
+	w.SetSolver(SequentialImpulseSolver)
+	w.SetWorkerPool(wp)
+
+	pusher := newBox(100, 188, 20, 20, 0)
+	pusher.SetBodyType(physics_components.KinematicBody)
+	w.Add(pusher)
+	addFloor(w)
+
+	stack := []entitysubset.RigidBodyFace{}
+	for i := 0; i < 3; i++ {
+		box := newBox(200, 270-float64(i)*41, 40, 40, 1)
+		w.Add(box)
+		stack = append(stack, box)
+	}
+
+	stepWorld(w, 120, nil)
+	pusher.SetVel(vector.NewVec2(200, 0))
+
+	return w, stack
+}
+
+func TestSequentialImpulseSolverMatchesWithWorkerPool(t *testing.T) {
+	wp := pool.NewWorkerPool(4)
+	defer wp.Close()
+
+	serial, serialBodies := newSleepingStackWorld(nil)
+	pooled, pooledBodies := newSleepingStackWorld(wp)
+
+	for i, body := range serialBodies {
+		if body.Awake() {
+			t.Fatalf("expected the stack to be asleep before the push, box %d is awake", i)
+		}
+	}
+
+	woke := false
+	for step := 0; step < 120; step++ {
+		serial.Step(testDt)
+		pooled.Step(testDt)
+
+		for i, body := range serialBodies {
+			other := pooledBodies[i]
+
+			if !body.Position().Equal(other.Position()) || body.Rotation() != other.Rotation() {
+				t.Fatalf("step %d: box %d is at %v without a pool and %v with one", step, i, body.Position(), other.Position())
+			}
+		}
+
+		woke = woke || serialBodies[0].Awake()
+	}
+
+	if !woke {
+		t.Error("expected the pusher to wake the stack")
+	}
+}
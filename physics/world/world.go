@@ -0,0 +1,667 @@
+package world
+
+import (
+	"math"
+	"sort"
+
+	"github.com/kainn9/tteokbokki/physics/broadphase"
+	physics_components "github.com/kainn9/tteokbokki/physics/components"
+	"github.com/kainn9/tteokbokki/physics/detector"
+	entitysubset "github.com/kainn9/tteokbokki/physics/entity_subset"
+	"github.com/kainn9/tteokbokki/physics/factory"
+	"github.com/kainn9/tteokbokki/physics/physics"
+	"github.com/kainn9/tteokbokki/physics/pool"
+	"github.com/kainn9/tteokbokki/physics/resolver"
+	"github.com/kainn9/tteokbokki/physics/solver"
+	"github.com/kainn9/tteokbokki/vector"
+)
+
+type SolverType int
+
+const (
+	// Position projection + impulses via resolver.HandleManifold.
+	ProjectionImpulseSolver SolverType = iota
+	// Iterative penetration constraints via solver.Solve.
+	SequentialImpulseSolver
+)
+
+// Bullets are swept to this separation(slightly overlapping) so the
+// impact is picked up by the regular collision response.
+const ccdTargetSeparation = -0.25
+
+// How far the default dynamic tree grows each bodies bounds.
+const treeMargin = 10.0
+
+// Called for pairs whose filters allow a collision, returning false
+// skips the pair for this step.
+type ShouldCollideFunc func(bodyA, bodyB entitysubset.RigidBodyFace) bool
+
+type WorldFace interface {
+	Add(particleOrBody entitysubset.ParticleFace)
+	Remove(particleOrBody entitysubset.ParticleFace)
+
+	Particles() []entitysubset.ParticleFace
+	RigidBodies() []entitysubset.RigidBodyFace
+
+	Gravity() float64
+	SetGravity(float64)
+
+	Solver() SolverType
+	SetSolver(SolverType)
+
+	SolverIterations() int
+	SetSolverIterations(int)
+
+	WarmStarting() bool
+	SetWarmStarting(bool)
+
+	Broadphase() broadphase.BroadphaseFace
+	SetBroadphase(broadphase.BroadphaseFace)
+
+	ShouldCollide() ShouldCollideFunc
+	SetShouldCollide(ShouldCollideFunc)
+
+	SensorListener() SensorListenerFace
+	SetSensorListener(SensorListenerFace)
+
+	ContactListener() ContactListenerFace
+	SetContactListener(ContactListenerFace)
+
+	SleepingEnabled() bool
+	SetSleepingEnabled(bool)
+
+	Islands() []*Island
+
+	AddJoint(solver.JointFace)
+	RemoveJoint(solver.JointFace)
+	Joints() []solver.JointFace
+
+	WorkerPool() pool.WorkerPoolFace
+	SetWorkerPool(pool.WorkerPoolFace)
+
+	Locked() bool
+
+	Step(dt float64)
+
+	Raycast(
+		origin, dir vector.Vec2Face,
+		maxDist float64,
+		mode RaycastMode,
+		filter func(entitysubset.RigidBodyFace) bool,
+	) []RaycastResult
+	ShapeCast(
+		moving entitysubset.RigidBodyFace,
+		motion vector.Vec2Face,
+		mode RaycastMode,
+		filter func(entitysubset.RigidBodyFace) bool,
+	) []ShapeCastResult
+
+	QueryPoint(
+		point vector.Vec2Face,
+		filter func(entitysubset.RigidBodyFace) bool,
+	) []entitysubset.RigidBodyFace
+	QueryAABB(
+		aabb broadphase.AABB,
+		filter func(entitysubset.RigidBodyFace) bool,
+	) []entitysubset.RigidBodyFace
+	QueryCircle(
+		center vector.Vec2Face,
+		radius float64,
+		filter func(entitysubset.RigidBodyFace) bool,
+	) []entitysubset.RigidBodyFace
+}
+
+type World struct {
+	particles []entitysubset.ParticleFace
+	bodies    []entitysubset.RigidBodyFace
+	// Position of each body in bodies, used to order pairs.
+	bodyIndex map[entitysubset.RigidBodyFace]int
+
+	broadphase    broadphase.BroadphaseFace
+	shouldCollide ShouldCollideFunc
+
+	sensorListener SensorListenerFace
+	sensorOverlaps *touchingPairs
+
+	contactListener ContactListenerFace
+	contacts        *touchingPairs
+
+	sleepingEnabled bool
+	islands         []*Island
+
+	joints []solver.JointFace
+	// Pairs linked by a joint that does not collide connected.
+	jointedPairs map[bodyPair]bool
+
+	// Nil runs everything on the callers goroutine.
+	workerPool pool.WorkerPoolFace
+	// One way platform pairs currently passing through each other.
+	oneWayPassing map[bodyPair]bool
+
+	gravity float64
+
+	solver           SolverType
+	solverIterations int
+	contactCache     solver.ContactCacheFace
+
+	// Set while a step is running, Add/Remove calls made
+	// during that time are queued until the step finishes.
+	locked  bool
+	pending []pendingChange
+}
+
+// Holds either a particle/body or a joint.
+type pendingChange struct {
+	particleOrBody entitysubset.ParticleFace
+	joint          solver.JointFace
+	remove         bool
+}
+
+func NewWorld(gravity float64) WorldFace {
+	return &World{
+		bodyIndex:        make(map[entitysubset.RigidBodyFace]int),
+		broadphase:       broadphase.NewDynamicTree(treeMargin),
+		gravity:          gravity,
+		solverIterations: 10,
+		contactCache:     solver.NewContactCache(),
+		sensorOverlaps:   newTouchingPairs(),
+		contacts:         newTouchingPairs(),
+		oneWayPassing:    make(map[bodyPair]bool),
+		jointedPairs:     make(map[bodyPair]bool),
+		sleepingEnabled:  true,
+	}
+}
+
+func (w *World) Add(particleOrBody entitysubset.ParticleFace) {
+	if w.locked {
+		w.pending = append(w.pending, pendingChange{particleOrBody: particleOrBody})
+		return
+	}
+
+	if body, ok := particleOrBody.(entitysubset.RigidBodyFace); ok {
+		w.bodyIndex[body] = len(w.bodies)
+		w.bodies = append(w.bodies, body)
+		w.broadphase.Insert(body)
+		return
+	}
+
+	w.particles = append(w.particles, particleOrBody)
+}
+
+func (w *World) Remove(particleOrBody entitysubset.ParticleFace) {
+	if w.locked {
+		w.pending = append(w.pending, pendingChange{particleOrBody: particleOrBody, remove: true})
+		return
+	}
+
+	if body, ok := particleOrBody.(entitysubset.RigidBodyFace); ok {
+		w.removeJointsOf(body)
+		w.bodies = removeEntity(w.bodies, body)
+		w.broadphase.Remove(body)
+		delete(w.bodyIndex, body)
+		w.reindexBodies()
+		return
+	}
+
+	w.particles = removeEntity(w.particles, particleOrBody)
+}
+
+func (w World) Particles() []entitysubset.ParticleFace {
+	return w.particles
+}
+
+func (w World) RigidBodies() []entitysubset.RigidBodyFace {
+	return w.bodies
+}
+
+func (w World) Gravity() float64 {
+	return w.gravity
+}
+
+func (w *World) SetGravity(gravity float64) {
+	w.gravity = gravity
+}
+
+func (w World) Solver() SolverType {
+	return w.solver
+}
+
+func (w *World) SetSolver(solverType SolverType) {
+	w.solver = solverType
+}
+
+func (w World) SolverIterations() int {
+	return w.solverIterations
+}
+
+func (w *World) SetSolverIterations(iterations int) {
+	w.solverIterations = iterations
+}
+
+func (w World) WarmStarting() bool {
+	return w.contactCache.WarmStarting()
+}
+
+// Turning warm starting off is mostly useful for debugging the solver.
+func (w *World) SetWarmStarting(warmStarting bool) {
+	w.contactCache.SetWarmStarting(warmStarting)
+}
+
+func (w World) Broadphase() broadphase.BroadphaseFace {
+	return w.broadphase
+}
+
+// Moves every body over to the new broadphase.
+func (w *World) SetBroadphase(bp broadphase.BroadphaseFace) {
+	for _, body := range w.bodies {
+		w.broadphase.Remove(body)
+		bp.Insert(body)
+	}
+
+	w.broadphase = bp
+}
+
+func (w World) ShouldCollide() ShouldCollideFunc {
+	return w.shouldCollide
+}
+
+// Nil lets every pair the filters allow collide.
+func (w *World) SetShouldCollide(fn ShouldCollideFunc) {
+	w.shouldCollide = fn
+}
+
+func (w World) WorkerPool() pool.WorkerPoolFace {
+	return w.workerPool
+}
+
+// Spreads the narrow phase and island solving over the pool, results
+// do not depend on how many workers it has. The SequentialImpulseSolver
+// steps exactly the same as without a pool. The ProjectionImpulseSolver
+// can end up somewhere slightly different, since pairs are all checked
+// before any are resolved. Nil goes back to running serially.
+func (w *World) SetWorkerPool(wp pool.WorkerPoolFace) {
+	w.workerPool = wp
+}
+
+func (w World) Locked() bool {
+	return w.locked
+}
+
+func (w *World) Step(dt float64) {
+	w.locked = true
+	w.updateJointedPairs()
+
+	switch w.solver {
+	case SequentialImpulseSolver:
+		w.stepSequentialImpulse(dt)
+	default:
+		w.stepProjectionImpulse(dt)
+	}
+
+	w.updateSleep(dt)
+
+	// Keeps queries made between steps accurate.
+	w.updateBroadphase()
+
+	w.locked = false
+	w.flushPending()
+	w.removeBrokenJoints()
+}
+
+func (w *World) stepProjectionImpulse(dt float64) {
+	for _, particle := range w.particles {
+		w.applyGravity(particle)
+		physics.Integrate(particle, dt)
+	}
+
+	for _, body := range w.bodies {
+		w.applyGravity(body)
+		physics.IntegrateForces(body, dt)
+	}
+
+	edges := w.jointEdges()
+
+	// Joints only fix up velocities, contacts are projected after moving.
+	jointIslands := w.buildIslands(edges)
+
+	w.forEachIndex(len(jointIslands), func(i int) {
+		solver.Solve(jointIslands[i].constraints, w.solverIterations, dt)
+	})
+
+	for _, body := range w.bodies {
+		w.integrateVelocities(body, dt)
+	}
+
+	w.forEachPair(func(pair pairResult) {
+		contact := w.narrowPhase(pair)
+		if contact == nil {
+			return
+		}
+		bodyA, bodyB := pair.bodyA, pair.bodyB
+		manifold := contact.Manifold
+		edges = append(edges, islandEdge{bodyA: bodyA, bodyB: bodyB, contact: contact})
+
+		normalImpulses, tangentImpulses := resolver.HandleManifoldWithMaterial(
+			manifold,
+			bodyA,
+			bodyB,
+			contact.Friction,
+			contact.Elasticity,
+		)
+
+		w.postSolveContact(contact, ContactImpulse{normalImpulses, tangentImpulses})
+	})
+
+	w.endSensorOverlaps()
+	w.endContacts()
+
+	// Contacts are already resolved, islands are only used for sleeping.
+	w.islands = w.buildIslands(edges)
+}
+
+// Velocities are updated first, then constraints fix them up
+// before anything is actually moved.
+func (w *World) stepSequentialImpulse(dt float64) {
+	for _, particle := range w.particles {
+		w.applyGravity(particle)
+		physics.IntegrateForces(particle, dt)
+	}
+
+	for _, body := range w.bodies {
+		w.applyGravity(body)
+		physics.IntegrateForces(body, dt)
+	}
+
+	// Joints come first, so they are solved before contacts.
+	edges := w.jointEdges()
+	w.contactCache.BeginStep()
+
+	w.forEachPair(func(pair pairResult) {
+		contact := w.narrowPhase(pair)
+		if contact == nil {
+			return
+		}
+		bodyA, bodyB := pair.bodyA, pair.bodyB
+		manifold := contact.Manifold
+
+		pcs := make([]solver.PenConstraintFace, len(manifold.Contacts))
+
+		for i, collision := range manifold.Contacts {
+			pc := w.contactCache.NewPenConstraint(collision, bodyA, bodyB)
+			pc.Constraint().Friction = contact.Friction
+			pc.Constraint().Elasticity = contact.Elasticity
+
+			pcs[i] = pc
+		}
+
+		edges = append(edges, islandEdge{bodyA: bodyA, bodyB: bodyB, contact: contact, pens: pcs})
+	})
+
+	w.endSensorOverlaps()
+	w.endContacts()
+
+	// Islands share no bodies, so solving them one at a time
+	// gives the same result as solving everything together.
+	w.islands = w.buildIslands(edges)
+
+	w.forEachIndex(len(w.islands), func(i int) {
+		solver.Solve(w.islands[i].constraints, w.solverIterations, dt)
+	})
+
+	for _, edge := range edges {
+		w.postSolveConstraints(edge)
+	}
+
+	for _, particle := range w.particles {
+		physics.IntegrateVelocities(particle, dt)
+	}
+
+	for _, body := range w.bodies {
+		w.integrateVelocities(body, dt)
+	}
+}
+
+// Bullets only move as far as their first impact with a static body,
+// sensors and one way platforms are ignored.
+func (w World) integrateVelocities(body entitysubset.RigidBodyFace, dt float64) {
+	if !body.Bullet() || body.Sensor() || !body.Awake() || physics.Util.IsStaticLinear(body) {
+		physics.IntegrateVelocities(body, dt)
+		return
+	}
+
+	motion := body.Vel().Scale(dt)
+	toi := 1.0
+
+	for _, other := range w.bodies {
+		if other == body || other.BodyType() != physics_components.StaticBody {
+			continue
+		}
+
+		if other.Sensor() || other.OneWay() != nil {
+			continue
+		}
+
+		if !w.canCollide(body, other) {
+			continue
+		}
+
+		if hit, otherToi := detector.TimeOfImpact(body, other, motion, ccdTargetSeparation); hit {
+			toi = math.Min(toi, otherToi)
+		}
+	}
+
+	if toi == 1 {
+		physics.IntegrateVelocities(body, dt)
+		return
+	}
+
+	body.SetPosition(body.Position().Add(motion.Scale(toi)))
+	body.SetRotation(body.Rotation() + body.AngularVel()*dt)
+}
+
+func (w World) applyGravity(particle entitysubset.ParticleFace) {
+	// Adding a force would wake sleeping bodies.
+	if w.gravity == 0 || !particle.Awake() {
+		return
+	}
+
+	physics.AddForce(particle, factory.Forces.NewWeightForce(particle, w.gravity))
+}
+
+// Narrow phase result for a pair, found without touching any shared
+// state so pairs can be checked concurrently.
+type pairResult struct {
+	bodyA, bodyB entitysubset.RigidBodyFace
+
+	sensor, resting, touching bool
+	// Only set for touching pairs without a sensor.
+	manifold *physics_components.Manifold
+}
+
+func detectPair(bodyA, bodyB entitysubset.RigidBodyFace) pairResult {
+	result := pairResult{bodyA: bodyA, bodyB: bodyB}
+
+	if isSensorPair(bodyA, bodyB) {
+		result.sensor = true
+		result.touching = detector.Overlaps(bodyA, bodyB)
+		return result
+	}
+
+	if isResting(bodyA, bodyB) {
+		result.resting = true
+		return result
+	}
+
+	result.touching, result.manifold = detector.CheckManifold(bodyA, bodyB)
+
+	return result
+}
+
+// Sensors, waking and contact callbacks for a pair, returns nil
+// when there is nothing to resolve.
+func (w World) narrowPhase(pair pairResult) *Contact {
+	bodyA, bodyB := pair.bodyA, pair.bodyB
+
+	if pair.sensor {
+		w.handleSensorPair(bodyA, bodyB, pair.touching)
+		return nil
+	}
+
+	if pair.resting {
+		w.contacts.keep(bodyPair{bodyA, bodyB})
+		return nil
+	}
+
+	if !pair.touching {
+		return nil
+	}
+
+	w.wakeTouching(bodyA, bodyB)
+
+	contact := w.touchContact(bodyA, bodyB, pair.manifold)
+	if !contact.Enabled {
+		return nil
+	}
+
+	return contact
+}
+
+// Runs the narrow phase on every broadphase pair that might be
+// colliding and calls fn with each result, in a stable order with A
+// always added to the world before B.
+// Without a worker pool each pair is checked right before fn, so it sees
+// where resolving the earlier pairs moved its bodies. With one every pair
+// is checked up front, spread over the pool, and pairs woken up by an
+// earlier one are checked again once they reach fn.
+func (w World) forEachPair(fn func(pair pairResult)) {
+	pairs := w.candidatePairs()
+
+	if w.workerPool == nil {
+		for _, pair := range pairs {
+			fn(detectPair(pair.BodyA, pair.BodyB))
+		}
+		return
+	}
+
+	results := make([]pairResult, len(pairs))
+
+	w.forEachIndex(len(pairs), func(i int) {
+		results[i] = detectPair(pairs[i].BodyA, pairs[i].BodyB)
+	})
+
+	for _, result := range results {
+		if result.resting && !isResting(result.bodyA, result.bodyB) {
+			result = detectPair(result.bodyA, result.bodyB)
+		}
+
+		fn(result)
+	}
+}
+
+func (w World) candidatePairs() []broadphase.Pair {
+	w.updateBroadphase()
+
+	pairs := w.broadphase.Pairs()
+
+	for i, pair := range pairs {
+		if w.bodyIndex[pair.BodyA] > w.bodyIndex[pair.BodyB] {
+			pairs[i] = broadphase.Pair{BodyA: pair.BodyB, BodyB: pair.BodyA}
+		}
+	}
+
+	sort.Slice(pairs, func(i, j int) bool {
+		indexA, indexB := w.bodyIndex[pairs[i].BodyA], w.bodyIndex[pairs[j].BodyA]
+		if indexA != indexB {
+			return indexA < indexB
+		}
+
+		return w.bodyIndex[pairs[i].BodyB] < w.bodyIndex[pairs[j].BodyB]
+	})
+
+	candidates := []broadphase.Pair{}
+
+	for _, pair := range pairs {
+		bodyA, bodyB := pair.BodyA, pair.BodyB
+
+		// Two bodies without mass can never resolve against each other,
+		// but a sensor still sees a kinematic body moving into it.
+		if physics.Util.IsStaticLinear(bodyA) && physics.Util.IsStaticLinear(bodyB) &&
+			(!isSensorPair(bodyA, bodyB) || isStaticPair(bodyA, bodyB)) {
+			continue
+		}
+
+		if !w.canCollide(bodyA, bodyB) {
+			continue
+		}
+
+		candidates = append(candidates, pair)
+	}
+
+	return candidates
+}
+
+func isSensorPair(bodyA, bodyB entitysubset.RigidBodyFace) bool {
+	return bodyA.Sensor() || bodyB.Sensor()
+}
+
+func isStaticPair(bodyA, bodyB entitysubset.RigidBodyFace) bool {
+	return bodyA.BodyType() == physics_components.StaticBody && bodyB.BodyType() == physics_components.StaticBody
+}
+
+// Calls fn for every i in [0, n), on the worker pool when one is set.
+func (w World) forEachIndex(n int, fn func(i int)) {
+	if w.workerPool == nil {
+		for i := 0; i < n; i++ {
+			fn(i)
+		}
+		return
+	}
+
+	w.workerPool.ForEach(n, fn)
+}
+
+func (w World) canCollide(bodyA, bodyB entitysubset.RigidBodyFace) bool {
+	if !bodyA.Filter().ShouldCollide(bodyB.Filter()) {
+		return false
+	}
+
+	if w.jointed(bodyA, bodyB) {
+		return false
+	}
+
+	return w.shouldCollide == nil || w.shouldCollide(bodyA, bodyB)
+}
+
+func (w World) updateBroadphase() {
+	for _, body := range w.bodies {
+		w.broadphase.Update(body)
+	}
+}
+
+func (w *World) reindexBodies() {
+	for i, body := range w.bodies {
+		w.bodyIndex[body] = i
+	}
+}
+
+func (w *World) flushPending() {
+	pending := w.pending
+	w.pending = nil
+
+	for _, change := range pending {
+		if change.joint != nil {
+			if change.remove {
+				w.RemoveJoint(change.joint)
+			} else {
+				w.AddJoint(change.joint)
+			}
+			continue
+		}
+
+		if change.remove {
+			w.Remove(change.particleOrBody)
+		} else {
+			w.Add(change.particleOrBody)
+		}
+	}
+}
@@ -0,0 +1,73 @@
+package world
+
+import (
+	"testing"
+
+	entitysubset "github.com/kainn9/tteokbokki/physics/entity_subset"
+	"github.com/kainn9/tteokbokki/physics/factory"
+)
+
+const testDt = 1.0 / 60.0
+
+var solvers = []struct {
+	name   string
+	solver SolverType
+}{
+	{"projection", ProjectionImpulseSolver},
+	{"sequential impulse", SequentialImpulseSolver},
+}
+
+// Runs test once per solver, each in a new world with the default gravity.
+func forEachSolver(t *testing.T, test func(t *testing.T, w WorldFace)) {
+	for _, s := range solvers {
+		t.Run(s.name, func(t *testing.T) {
+			w := NewWorld(factory.Forces.DEFAULT_GRAVITY)
+			w.SetSolver(s.solver)
+
+			test(t, w)
+		})
+	}
+}
+
+// Steps w n times, calling check after each step.
+func stepWorld(w WorldFace, n int, check func(step int)) {
+	for i := 0; i < n; i++ {
+		w.Step(testDt)
+
+		if check != nil {
+			check(i)
+		}
+	}
+}
+
+func newBox(x, y, width, height, mass float64) entitysubset.RigidBodyFace {
+	trans, shape, phys := factory.Components.NewRigidBodyRectangleComponents(x, y, width, height, mass, 0)
+	body := entitysubset.NewRigidBody(trans, shape, phys)
+
+	if mass != 0 {
+		body.SetAndCalculateAngularMass(body)
+	}
+	body.SetFriction(0.5)
+
+	return body
+}
+
+func newCircle(x, y, radius, mass float64) entitysubset.RigidBodyFace {
+	trans, shape, phys := factory.Components.NewRigidBodyCircleComponents(x, y, radius, mass, 0)
+	body := entitysubset.NewRigidBody(trans, shape, phys)
+
+	if mass != 0 {
+		body.SetAndCalculateAngularMass(body)
+	}
+	body.SetFriction(0.5)
+
+	return body
+}
+
+// Static and 400 wide, with its top at y = 290.
+func addFloor(w WorldFace) entitysubset.RigidBodyFace {
+	floor := newBox(200, 300, 400, 20, 0)
+	w.Add(floor)
+
+	return floor
+}
@@ -0,0 +1,38 @@
+package transform_components
+
+import (
+	"math"
+
+	"github.com/kainn9/tteokbokki/vector"
+)
+
+// Points exactly on the edge count as inside for every shape.
+func (circle Circle) ContainsPoint(center, point vector.Vec2Face) bool {
+	return point.Sub(center).MagSquared() <= circle.radius*circle.radius
+}
+
+// Uses the world vertices, so UpdateWorldVertices must be current.
+func (polygon Polygon) ContainsPoint(point vector.Vec2Face) bool {
+	if len(polygon.worldVertices) == 0 {
+		return false
+	}
+
+	for i, vert := range polygon.worldVertices {
+		edge, _, _ := polygon.Edge(i)
+
+		// Edge normals point out, so any positive distance is outside.
+		if edge.Perpendicular().ScalarProduct(point.Sub(vert)) > 0 {
+			return false
+		}
+	}
+
+	return true
+}
+
+// Ignores rotation, like the rest of the AAB methods.
+func (rectangle AAB) ContainsPoint(t TransformFace, point vector.Vec2Face) bool {
+	offset := point.Sub(t.Position())
+
+	return math.Abs(offset.X()) <= rectangle.ScaledWidth(t)/2 &&
+		math.Abs(offset.Y()) <= rectangle.ScaledHeight(t)/2
+}
@@ -0,0 +1,124 @@
+package transform_components
+
+import (
+	"math"
+
+	"github.com/kainn9/tteokbokki/vector"
+)
+
+type RaycastHit struct {
+	Point, Normal vector.Vec2Face
+	// Distance travelled along the ray divided by maxDist.
+	Fraction float64
+	// Index of the polygon edge that was hit, -1 for circles.
+	EdgeIndex int
+}
+
+// Rays starting inside the circle do not hit it.
+func (circle Circle) Raycast(
+	center, origin, dir vector.Vec2Face,
+	maxDist float64,
+) (hit bool, result RaycastHit) {
+	if degenerateRay(dir, maxDist) {
+		return false, result
+	}
+
+	dir = dir.Norm()
+	originToCenter := origin.Sub(center)
+
+	// Solve |originToCenter + dir*t| = radius for t.
+	b := originToCenter.ScalarProduct(dir)
+	c := originToCenter.MagSquared() - circle.radius*circle.radius
+
+	if c <= 0 {
+		return false, result
+	}
+
+	discriminant := b*b - c
+
+	if discriminant < 0 {
+		return false, result
+	}
+
+	t := -b - math.Sqrt(discriminant)
+
+	if t < 0 || t > maxDist {
+		return false, result
+	}
+
+	point := origin.Add(dir.Scale(t))
+
+	return true, RaycastHit{
+		Point:     point,
+		Normal:    point.Sub(center).Norm(),
+		Fraction:  t / maxDist,
+		EdgeIndex: -1,
+	}
+}
+
+// Clips the ray against every edge(Cyrus–Beck), using the world
+// vertices so UpdateWorldVertices must be current. Rays starting
+// inside the polygon do not hit it.
+func (polygon Polygon) Raycast(
+	origin, dir vector.Vec2Face,
+	maxDist float64,
+) (hit bool, result RaycastHit) {
+	if degenerateRay(dir, maxDist) {
+		return false, result
+	}
+
+	dir = dir.Norm()
+
+	lower, upper := 0.0, maxDist
+	index := -1
+
+	for i, vert := range polygon.worldVertices {
+		edge, _, _ := polygon.Edge(i)
+		normal := edge.Perpendicular().Norm()
+
+		numerator := normal.ScalarProduct(vert.Sub(origin))
+		denominator := normal.ScalarProduct(dir)
+
+		if denominator == 0 {
+			// Parallel and outside this edge.
+			if numerator < 0 {
+				return false, result
+			}
+			continue
+		}
+
+		t := numerator / denominator
+
+		if denominator < 0 && t > lower {
+			// Entering through this edge.
+			lower = t
+			index = i
+		} else if denominator > 0 && t < upper {
+			// Leaving through this edge.
+			upper = t
+		}
+
+		if upper < lower {
+			return false, result
+		}
+	}
+
+	if index < 0 {
+		return false, result
+	}
+
+	edge, _, _ := polygon.Edge(index)
+
+	return true, RaycastHit{
+		Point:     origin.Add(dir.Scale(lower)),
+		Normal:    edge.Perpendicular().Norm(),
+		Fraction:  lower / maxDist,
+		EdgeIndex: index,
+	}
+}
+
+// Rays with no length or direction would divide by zero, so they
+// never hit anything.
+func degenerateRay(dir vector.Vec2Face, maxDist float64) bool {
+	return !(maxDist > 0) || dir.MagSquared() == 0
+}
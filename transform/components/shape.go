@@ -22,6 +22,9 @@ type Shape struct {
 type CircleFace interface {
 	Radius() float64
 	Area() float64
+
+	Raycast(center, origin, dir vector.Vec2Face, maxDist float64) (hit bool, result RaycastHit)
+	ContainsPoint(center, point vector.Vec2Face) bool
 }
 type Circle struct {
 	radius float64
@@ -43,6 +46,9 @@ type PolygonFace interface {
 
 	CircleSkin() CircleFace
 	CalculateAndSetCircleSkin()
+
+	Raycast(origin, dir vector.Vec2Face, maxDist float64) (hit bool, result RaycastHit)
+	ContainsPoint(point vector.Vec2Face) bool
 }
 
 type Polygon struct {
@@ -59,6 +65,8 @@ type AABFace interface {
 
 	ScaledWidth(t TransformFace) float64
 	ScaledHeight(t TransformFace) float64
+
+	ContainsPoint(t TransformFace, point vector.Vec2Face) bool
 }
 type AAB struct {
 	width  float64